@@ -0,0 +1,90 @@
+package redis
+
+//BitFieldType is the type of an integer within a BitField: signed or unsigned, and how many bits wide it is
+//(up to 64 bits for signed integers, and 63 bits for unsigned integers)
+type BitFieldType string
+
+//Signed is the type of a signed integer that is "width" bits wide
+func Signed(width int) BitFieldType {
+	return BitFieldType("i" + itoa(width))
+}
+
+//Unsigned is the type of an unsigned integer that is "width" bits wide
+func Unsigned(width int) BitFieldType {
+	return BitFieldType("u" + itoa(width))
+}
+
+//BitFieldOverflow decides what happens when setting or incrementing an integer goes past what it can hold
+type BitFieldOverflow string
+
+const (
+	//BitFieldWrap wraps around, like most programming languages do (this is the default)
+	BitFieldWrap BitFieldOverflow = "WRAP"
+
+	//BitFieldSaturate stays at the lowest or highest value the integer can hold
+	BitFieldSaturate BitFieldOverflow = "SAT"
+
+	//BitFieldFail leaves the integer alone, and returns nil as the result of the operation
+	BitFieldFail BitFieldOverflow = "FAIL"
+)
+
+//BitField keeps track of a series of operations on integers of arbitrary widths stored within Bits,
+//which are all run at once (and atomically) with Run.
+//Offsets are measured in bits from the beginning of the string; the "At" operations instead treat the string as an array of integers of the same type,
+//so that index 2 of an Unsigned(4) array is 8 bits from the beginning
+type BitField struct {
+	key  Key
+	args []string
+}
+
+//Fields creates a BitField to help build up a series of operations on the integers within the bits
+func (this Bits) Fields() *BitField {
+	return &BitField{key: this.Key}
+}
+
+func (this *BitField) add(args ...string) *BitField {
+	this.args = append(this.args, args...)
+	return this
+}
+
+//Get reads the integer at the offset
+func (this *BitField) Get(t BitFieldType, offset int) *BitField {
+	return this.add("GET", string(t), itoa(offset))
+}
+
+//GetAt reads the integer at the index of an array of integers
+func (this *BitField) GetAt(t BitFieldType, index int) *BitField {
+	return this.add("GET", string(t), "#"+itoa(index))
+}
+
+//Set sets the integer at the offset; its result is the value it had before
+func (this *BitField) Set(t BitFieldType, offset, value int) *BitField {
+	return this.add("SET", string(t), itoa(offset), itoa(value))
+}
+
+//SetAt sets the integer at the index of an array of integers; its result is the value it had before
+func (this *BitField) SetAt(t BitFieldType, index, value int) *BitField {
+	return this.add("SET", string(t), "#"+itoa(index), itoa(value))
+}
+
+//IncrementBy adds to the integer at the offset (or subtracts, if "increment" is negative); its result is the new value
+func (this *BitField) IncrementBy(t BitFieldType, offset, increment int) *BitField {
+	return this.add("INCRBY", string(t), itoa(offset), itoa(increment))
+}
+
+//IncrementAt adds to the integer at the index of an array of integers; its result is the new value
+func (this *BitField) IncrementAt(t BitFieldType, index, increment int) *BitField {
+	return this.add("INCRBY", string(t), "#"+itoa(index), itoa(increment))
+}
+
+//Overflow changes what happens when any of the following Set and IncrementBy operations overflow
+func (this *BitField) Overflow(overflow BitFieldOverflow) *BitField {
+	return this.add("OVERFLOW", string(overflow))
+}
+
+//BITFIELD command -
+//Run runs every operation, and returns the result of each Get, Set, and IncrementBy in order.
+//An operation that failed with BitFieldFail has a nil result
+func (this *BitField) Run() <-chan []*int {
+	return maybeIntsChannel(MaybeSliceCommand(this.key, this.key.args("bitfield", this.args...)...))
+}
@@ -6,6 +6,21 @@ type Bits struct {
 	Key
 }
 
+//BitRangeUnit is what the start and end of a range of Bits are measured in
+type BitRangeUnit string
+
+const (
+	ByteRange BitRangeUnit = "BYTE"
+	BitRange  BitRangeUnit = "BIT"
+)
+
+func bitValue(on bool) string {
+	if on {
+		return "1"
+	}
+	return "0"
+}
+
 func newBits(client SafeExecutor, key string) Bits {
 	return Bits{
 		newKey(client, key),
@@ -50,11 +65,38 @@ func (this Bits) Get(index int) <-chan bool {
 }
 
 //BITCOUNT command - 
-//Count returns the number of bits that are set
+//Count returns the number of bits that are set between the "start" and "end" bytes (inclusive).
+//Negative numbers count back from the end, with -1 being the last byte
 func (this Bits) Count(start, end int) <-chan int {
+	return IntCommand(this, this.args("bitcount", itoa(start), itoa(end))...)
+}
+
+//BITCOUNT command -
+//CountAll returns the number of bits that are set
+func (this Bits) CountAll() <-chan int {
 	return IntCommand(this, this.args("bitcount")...)
 }
 
+//BITCOUNT command -
+//CountIn returns the number of bits that are set between "start" and "end" (inclusive), measured in either bytes or bits
+func (this Bits) CountIn(start, end int, unit BitRangeUnit) <-chan int {
+	return IntCommand(this, this.args("bitcount", itoa(start), itoa(end), string(unit))...)
+}
+
+//BITPOS command -
+//Position returns the index of the first bit that is set to "on".
+//If none of the bits are on, -1 is returned; if none are off, the index just past the end of the string is returned
+func (this Bits) Position(on bool) <-chan int {
+	return IntCommand(this, this.args("bitpos", bitValue(on))...)
+}
+
+//BITPOS command -
+//PositionIn returns the index of the first bit that is set to "on" between "start" and "end" (inclusive), measured in either bytes or bits.
+//The index is counted from the beginning of the string; -1 is returned if there isn't such a bit within the range
+func (this Bits) PositionIn(on bool, start, end int, unit BitRangeUnit) <-chan int {
+	return IntCommand(this, this.args("bitpos", bitValue(on), itoa(start), itoa(end), string(unit))...)
+}
+
 //BITOP AND command - 
 //StoreIntersetionOf stores the result of a logical and operation of other bitfields in this bitfield
 func (this Bits) StoreIntersectionOf(otherKeys ...Bits) <-chan int {
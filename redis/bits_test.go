@@ -116,3 +116,62 @@ func TestBitsFuncs(t *testing.T) {
 	}
 
 }
+
+func TestBitsRanges(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	b := r.Bits("Test_Bits_Ranges")
+	<-b.Delete()
+	<-b.On(1)
+	<-b.On(9)
+	<-b.On(10)
+	<-b.On(23)
+
+	if count := <-b.CountAll(); count != 4 {
+		t.Error("There should be 4 bits set, not", count)
+	}
+	if count := <-b.Count(1, -1); count != 3 {
+		t.Error("There should be 3 bits set after the first byte, not", count)
+	}
+	if count := <-b.CountIn(2, 9, BitRange); count != 1 {
+		t.Error("There should be 1 bit set between bits 2 and 9, not", count)
+	}
+	if position := <-b.Position(true); position != 1 {
+		t.Error("The first bit set should be 1, not", position)
+	}
+	if position := <-b.Position(false); position != 0 {
+		t.Error("The first bit unset should be 0, not", position)
+	}
+	if position := <-b.PositionIn(true, 1, 2, ByteRange); position != 9 {
+		t.Error("The first bit set from the second byte should be 9, not", position)
+	}
+	if position := <-b.PositionIn(true, 11, 22, BitRange); position != -1 {
+		t.Error("There shouldn't be any bits set between bits 11 and 22, not", position)
+	}
+}
+
+func TestBitField(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	b := r.Bits("Test_BitField")
+	<-b.Delete()
+
+	counter := Unsigned(4)
+	results := <-b.Fields().SetAt(counter, 0, 3).IncrementAt(counter, 1, 5).IncrementAt(counter, 1, 12).GetAt(counter, 0).Run()
+	if len(results) != 4 || results[0] == nil || *results[0] != 0 || results[1] == nil || *results[1] != 5 ||
+		results[2] == nil || *results[2] != 1 || results[3] == nil || *results[3] != 3 {
+		t.Error("Wrapping counters gave the wrong results:", results)
+	}
+
+	results = <-b.Fields().Overflow(BitFieldSaturate).IncrementAt(counter, 0, 100).Overflow(BitFieldFail).IncrementAt(counter, 1, 100).Run()
+	if len(results) != 2 || results[0] == nil || *results[0] != 15 || results[1] != nil {
+		t.Error("Saturating and failing counters gave the wrong results:", results)
+	}
+
+	results = <-b.Fields().Set(Signed(8), 8, -5).Get(Signed(8), 8).Get(Unsigned(8), 8).Run()
+	if len(results) != 3 || results[1] == nil || *results[1] != -5 || results[2] == nil || *results[2] != 251 {
+		t.Error("Signed integers gave the wrong results:", results)
+	}
+}
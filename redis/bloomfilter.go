@@ -0,0 +1,210 @@
+package redis
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+)
+
+//BloomFilter keeps track of which items have been added to it, using a fixed number of Bits no matter how many items there are.
+//Checking for an item that was added is always right, but checking for one that wasn't might wrongly say that it was
+//(about as often as the false positive rate it was sized for, once it holds the expected number of items).
+//The positions of an item's bits are worked out locally, and they're all set or read with a single round trip
+type BloomFilter struct {
+	bits   Bits
+	size   int
+	hashes int
+}
+
+//BloomFilter sizes a bloom filter to hold "expectedItems" with the specified false positive rate (e.g. 0.01 for 1%), using these bits.
+//Every BloomFilter that shares the same bits must be created with the same expected items and false positive rate.
+//This is a lightweight function - does *not* involve network I/O
+func (this Bits) BloomFilter(expectedItems int, falsePositiveRate float64) BloomFilter {
+	if expectedItems < 1 {
+		expectedItems = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	size := int(math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := int(math.Round(float64(size) / float64(expectedItems) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return BloomFilter{
+		bits:   this,
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+//Bits returns the bits that the bloom filter is stored in
+func (this BloomFilter) Bits() Bits {
+	return this.bits
+}
+
+//Size returns the number of bits the bloom filter uses
+func (this BloomFilter) Size() int {
+	return this.size
+}
+
+//Hashes returns the number of bits that are set for each item
+func (this BloomFilter) Hashes() int {
+	return this.hashes
+}
+
+//positions works out which bits belong to an item, by combining two halves of a single hash
+//(see "Less Hashing, Same Performance: Building a Better Bloom Filter" by Kirsch and Mitzenmacher)
+func (this BloomFilter) positions(item string) []int {
+	hash := fnv.New128a()
+	hash.Write([]byte(item))
+	sum := hash.Sum(nil)
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:])
+
+	result := make([]int, this.hashes)
+	for i := range result {
+		result[i] = int((h1 + uint64(i)*h2) % uint64(this.size))
+	}
+	return result
+}
+
+//SETBIT command -
+//Add adds an item to the bloom filter; returns whether or not it is new (as opposed to already having been added, probably)
+func (this BloomFilter) Add(item string) <-chan bool {
+	return firstBool(this.AddAll(item))
+}
+
+//SETBIT command -
+//AddAll adds every item to the bloom filter at once; returns whether or not each of them is new, in the same order
+func (this BloomFilter) AddAll(items ...string) <-chan []bool {
+	//an item is new if any of its bits weren't set before
+	return this.run(items, Bits.On, false)
+}
+
+//GETBIT command -
+//Contains returns whether or not the item has (probably) been added to the bloom filter
+func (this BloomFilter) Contains(item string) <-chan bool {
+	return firstBool(this.ContainsAll(item))
+}
+
+//GETBIT command -
+//ContainsAll checks every item at once; returns whether or not each of them has (probably) been added, in the same order
+func (this BloomFilter) ContainsAll(items ...string) <-chan []bool {
+	//an item has been added if all of its bits are set
+	return this.run(items, Bits.Get, true)
+}
+
+//run issues a command for every bit of every item, and checks each item's bits:
+//if "all" is set, an item's result is whether all of its bits are on, otherwise it is whether any of them are off.
+//The commands are sent through a Pipeline when the bloom filter belongs to a Client;
+//within a Pipeline or Transaction, they're simply issued as part of it
+func (this BloomFilter) run(items []string, command func(Bits, int) <-chan bool, all bool) <-chan []bool {
+	issue := func(bits Bits) []<-chan bool {
+		results := make([]<-chan bool, 0, len(items)*this.hashes)
+		for _, item := range items {
+			for _, position := range this.positions(item) {
+				results = append(results, command(bits, position))
+			}
+		}
+		return results
+	}
+	collect := func(out chan<- []bool, bits []<-chan bool) {
+		result := make([]bool, len(items))
+		for i := range result {
+			on := true
+			for _, bit := range bits[i*this.hashes : (i+1)*this.hashes] {
+				value, ok := <-bit
+				if !ok {
+					return
+				}
+				on = on && value
+			}
+			result[i] = on == all
+		}
+		out <- result
+	}
+
+	out := make(chan []bool, 1)
+	client, ok := this.bits.client.(*Client)
+	if !ok {
+		bits := issue(this.bits)
+		go func() {
+			defer close(out)
+			collect(out, bits)
+		}()
+		return out
+	}
+	go func() {
+		defer close(out)
+		var bits []<-chan bool
+		result := client.Pipeline(func(e SafeExecutor) {
+			bits = issue(this.bits.Use(e))
+		})
+		//the errors of single commands have already been sent to the error callback
+		if result.Err != nil {
+			client.errCallback(result.Err, "Bloom filter "+this.bits.key)
+		}
+		if !result.Succeeded() {
+			return
+		}
+		collect(out, bits)
+	}()
+	return out
+}
+
+func firstBool(in <-chan []bool) <-chan bool {
+	out := make(chan bool, 1)
+	go func() {
+		defer close(out)
+		if results, ok := <-in; ok && len(results) > 0 {
+			out <- results[0]
+		}
+	}()
+	return out
+}
+
+//BITCOUNT command -
+//EstimatedCount estimates how many unique items have been added to the bloom filter, from how many of its bits are set
+func (this BloomFilter) EstimatedCount() <-chan int {
+	out := make(chan int, 1)
+	in := this.bits.CountAll()
+	go func() {
+		defer close(out)
+		set, ok := <-in
+		if !ok {
+			return
+		}
+		if set >= this.size {
+			set = this.size - 1
+		}
+		m, k := float64(this.size), float64(this.hashes)
+		out <- int(math.Round(-m / k * math.Log(1-float64(set)/m)))
+	}()
+	return out
+}
+
+//BITOP OR command -
+//StoreUnionOf combines other bloom filters into this one, so that it contains every item that was added to any of them.
+//They must all have been sized the same way
+func (this BloomFilter) StoreUnionOf(others ...BloomFilter) <-chan int {
+	bits := make([]Bits, 0, len(others)+1)
+	bits = append(bits, this.bits)
+	for _, other := range others {
+		if other.size != this.size || other.hashes != this.hashes {
+			this.bits.client.errCallback(errors.New("Bloom filters must be the same size to be combined"), "Combining "+other.bits.key+" into "+this.bits.key)
+			out := make(chan int)
+			close(out)
+			return out
+		}
+		bits = append(bits, other.bits)
+	}
+	return this.bits.StoreUnionOf(bits...)
+}
+
+//Use allows you to use this bloom filter on a different executor
+func (this BloomFilter) Use(e SafeExecutor) BloomFilter {
+	this.bits = this.bits.Use(e)
+	return this
+}
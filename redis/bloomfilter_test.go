@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"testing"
+)
+
+func TestBloomFilterSizing(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	bloom := r.BloomFilter("Test_Bloom:Sizing", 1000, 0.01)
+	if bloom.Size() != 9586 {
+		t.Error("1000 items at 1% should take 9586 bits, not", bloom.Size())
+	}
+	if bloom.Hashes() != 7 {
+		t.Error("1000 items at 1% should take 7 hashes, not", bloom.Hashes())
+	}
+
+	positions := bloom.positions("item")
+	if len(positions) != bloom.Hashes() {
+		t.Fatal("Expected a position for each hash, got", positions)
+	}
+	for i, position := range bloom.positions("item") {
+		if position < 0 || position >= bloom.Size() || position != positions[i] {
+			t.Error("Positions should be stable and within the filter, got", positions)
+		}
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_Bloom:")
+	a, b, union := p.BloomFilter("A", 100, 0.01), p.BloomFilter("B", 100, 0.01), p.BloomFilter("Union", 100, 0.01)
+	<-a.Bits().Delete()
+	<-b.Bits().Delete()
+	<-union.Bits().Delete()
+
+	if added := <-a.Add("x"); !added {
+		t.Error("x should be new")
+	}
+	if added := <-a.Add("x"); added {
+		t.Error("x shouldn't be new the second time")
+	}
+	if found := <-a.Contains("x"); !found {
+		t.Error("A should contain x")
+	}
+	if found := <-a.Contains("nothing"); found {
+		t.Error("A shouldn't contain an item that wasn't added")
+	}
+
+	if added := <-a.AddAll("x", "y", "z"); len(added) != 3 || added[0] || !added[1] || !added[2] {
+		t.Error("Only y and z should be new, got", added)
+	}
+	if found := <-a.ContainsAll("z", "w", "y"); len(found) != 3 || !found[0] || found[1] || !found[2] {
+		t.Error("A should contain z and y but not w, got", found)
+	}
+	if found := <-a.ContainsAll(); len(found) != 0 {
+		t.Error("Checking no items should find nothing, got", found)
+	}
+	if count := <-a.EstimatedCount(); count != 3 {
+		t.Error("A should hold about 3 items, not", count)
+	}
+
+	var added <-chan []bool
+	r.Transaction(func(e SafeExecutor) {
+		added = b.Use(e).AddAll("v", "w")
+	})
+	if result := <-added; len(result) != 2 || !result[0] || !result[1] {
+		t.Error("v and w should be new within a transaction, got", result)
+	}
+	<-union.StoreUnionOf(a, b)
+	if found := <-union.ContainsAll("v", "w", "x", "y", "z"); len(found) != 5 || !found[0] || !found[1] || !found[2] || !found[3] || !found[4] {
+		t.Error("The union should contain every item, got", found)
+	}
+
+	errors := make(chan error, 1)
+	r.SetErrorCallback(func(err error, message string) {
+		select {
+		case errors <- err:
+		default:
+		}
+	})
+	if _, ok := <-union.StoreUnionOf(p.BloomFilter("Other", 10, 0.1)); ok {
+		t.Error("Combining bloom filters of different sizes should fail")
+	}
+	if err := <-errors; err == nil {
+		t.Error("Expected an error combining bloom filters of different sizes")
+	}
+}
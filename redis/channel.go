@@ -3,10 +3,16 @@ package redis
 import (
 	"errors"
 	"io"
+	"strings"
+	"time"
 )
 
 const (
 	messageBufferSize = 64
+
+	//reconnectMinDelay and reconnectMaxDelay bound how long a lost subscription waits before trying to reconnect
+	reconnectMinDelay = 100 * time.Millisecond
+	reconnectMaxDelay = 30 * time.Second
 )
 
 //A Channel is object that encapsulates the Pub/Sub redis commands.
@@ -14,6 +20,7 @@ const (
 type Channel struct {
 	Key
 	client *Client
+	buffer bufferPolicy
 }
 
 func newChannel(client *Client, key string) Channel {
@@ -35,50 +42,241 @@ func (this *subscription) Close() error {
 	return nil
 }
 
-func messageLoop(conn *Connection, errCallback errCallbackFunc) <-chan string {
-	output := make(chan string, messageBufferSize)
+//The kinds of Message that can arrive on a subscription connection
+const (
+	KindMessage            = "message"
+	KindPatternMessage     = "pmessage"
+	KindSubscribe          = "subscribe"
+	KindUnsubscribe        = "unsubscribe"
+	KindPatternSubscribe   = "psubscribe"
+	KindPatternUnsubscribe = "punsubscribe"
+	KindShardMessage       = "smessage"
+	KindShardSubscribe     = "ssubscribe"
+	KindShardUnsubscribe   = "sunsubscribe"
+	KindPong               = "pong"
+
+	//KindReconnect is sent once a lost subscription has been reestablished;
+	//any messages published while it was lost have been missed, and the Payload describes what went wrong
+	KindReconnect = "reconnect"
+)
+
+//A Message is anything that redis sends back on a subscription connection:
+//a message published on a channel, a confirmation that a (un)subscription has happened, or the reply to a PING
+type Message struct {
+	//Kind is one of the Kind constants, such as KindMessage
+	Kind string
+
+	//Channel is the channel a message was published on, or the channel that was (un)subscribed to
+	Channel string
+
+	//Pattern is the pattern that matched a message's channel, or the pattern that was (un)subscribed to
+	Pattern string
+
+	//Payload is the contents of a message, or whatever was sent along with a PING
+	Payload string
+
+	//Count is the number of channels and patterns the connection is still subscribed to, after a confirmation
+	Count int
+}
+
+//IsMessage returns whether or not the Message was published on a channel (as opposed to being a confirmation, a PONG, or a notice of a reconnection)
+func (this Message) IsMessage() bool {
+	return this.Kind == KindMessage || this.Kind == KindPatternMessage || this.Kind == KindShardMessage
+}
+
+func parseMessage(r *response) (Message, bool) {
+	//a connection that isn't subscribed to anything replies to PING normally, with either PONG or whatever was sent along with it
+	if r != nil && r.subresponses == nil {
+		m := Message{Kind: KindPong, Payload: r.val}
+		if m.Payload == "PONG" {
+			m.Payload = ""
+		}
+		return m, true
+	}
+
+	values := r.values()
+	if len(values) < 2 {
+		return Message{}, false
+	}
+
+	m := Message{Kind: values[0]}
+	switch m.Kind {
+	case KindMessage, KindShardMessage:
+		if len(values) < 3 {
+			return m, false
+		}
+		m.Channel = values[1]
+		m.Payload = values[2]
+	case KindPatternMessage:
+		if len(values) < 4 {
+			return m, false
+		}
+		m.Pattern = values[1]
+		m.Channel = values[2]
+		m.Payload = values[3]
+	case KindSubscribe, KindUnsubscribe, KindShardSubscribe, KindShardUnsubscribe:
+		m.Channel = values[1]
+		if len(values) > 2 {
+			m.Count, _ = atoi(values[2])
+		}
+	case KindPatternSubscribe, KindPatternUnsubscribe:
+		m.Pattern = values[1]
+		if len(values) > 2 {
+			m.Count, _ = atoi(values[2])
+		}
+	case KindPong:
+		m.Payload = values[1]
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+//messageLoop hands everything that arrives on a subscription connection to "send" until it stops working,
+//or until "done" is closed or "send" returns false (in which case the connection gets closed, and nil is returned).
+//The first reply (the confirmation of the subscription) is handed to "subscribed" instead
+func messageLoop(conn *Connection, send func(Message) bool, done <-chan nothing, subscribed func(Message) bool) error {
+	finished := make(chan nothing)
+	defer close(finished)
 	go func() {
-		defer close(output)
-		defer func() {
-			if rec := recover(); rec != nil {
-				errCallback(getError(rec), "Closing a Channel")
-			}
-		}()
-		working := true
-		for working {
-			response, err := getResponse(conn)
-			if err != nil {
-				errCallback.Call(err, "Message Loop Error")
-				working = false
+		select {
+		case <-done:
+			conn.Close()
+		case <-finished:
+		}
+	}()
+
+	first := true
+	for {
+		response, err := getResponse(conn)
+		if err != nil {
+			select {
+			case <-done:
+				return nil
+			default:
+				return err
 			}
+		}
 
-			switch response.subresponses[0].val {
-			case "unsubscribe":
-				working = false
-			case "message":
-				output <- response.subresponses[2].val
-			case "pmessage":
-				output <- response.subresponses[3].val
+		m, ok := parseMessage(response)
+		switch {
+		case !ok:
+		case first:
+			first = false
+			if !subscribed(m) {
+				return nil
+			}
+		default:
+			if !send(m) {
+				return nil
 			}
 		}
+	}
+}
+
+//listen keeps a subscription going until "done" is closed, reconnecting and resubscribing whenever the connection is lost.
+//"started" is sent a value once the first subscription has been confirmed, or is closed if it never is
+func (this Channel) listen(output chan Message, done <-chan nothing, started chan<- nothing, sub string) {
+	defer close(output)
+	defer func() {
+		if started != nil {
+			close(started)
+		}
 	}()
-	return output
+
+	overflowed := false
+	send := func(m Message) bool {
+		carryOn, full := this.buffer.deliver(output, m, done)
+		overflowed = overflowed || full
+		return carryOn
+	}
+
+	delay := time.Duration(0)
+	var lost error
+	for !this.client.isClosed {
+		if lost != nil {
+			select {
+			case <-time.After(delay):
+			case <-done:
+				return
+			}
+		}
+
+		conn, err := this.client.newConnection()
+		if err == nil {
+			err = conn.input(nilCommand{args: this.args(sub)})
+		}
+		if err == nil {
+			err = messageLoop(conn, send, done, func(confirmation Message) bool {
+				if !send(confirmation) {
+					return false
+				}
+				if started != nil {
+					started <- nothing{}
+					close(started)
+					started = nil
+				}
+				delay = 0
+				if lost != nil {
+					return send(Message{Kind: KindReconnect, Payload: lost.Error()})
+				}
+				return true
+			})
+		}
+		if conn != nil {
+			conn.Close()
+		}
+		if overflowed {
+			this.client.errCallback(errSlowConsumer, "Subscription to "+this.key)
+			return
+		}
+		if err == nil {
+			return
+		}
+		if _, ok := err.(ReplyError); ok {
+			//redis refused the subscription, so trying again won't help
+			this.client.errCallback(err, strings.Join(this.args(sub), " "))
+			return
+		}
+
+		this.client.errCallback(err, "Lost subscription to "+this.key)
+		lost = err
+		delay = nextReconnectDelay(delay)
+	}
+}
+
+//nextReconnectDelay backs off exponentially between attempts to reconnect a subscription
+func nextReconnectDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay < reconnectMinDelay {
+		return reconnectMinDelay
+	}
+	if delay > reconnectMaxDelay {
+		return reconnectMaxDelay
+	}
+	return delay
 }
 
-func (this Channel) subscribe(action func(string), sub, unsub string) (startSignal <-chan nothing, finishSignaler io.Closer) {
+func (this Channel) subscribe(action func(Message), sub string) (startSignal <-chan nothing, finishSignaler io.Closer) {
 	closer := make(chan bool, 1)
 	happened := make(chan nothing, 1)
-	go this.blockingSubscription(func(messages <-chan string) {
-		happened <- nothing{}
+	messages := make(chan Message, this.buffer.capacity())
+	done := make(chan nothing)
+	go this.listen(messages, done, happened, sub)
+	go func() {
+		defer close(done)
 		for {
 			select {
-			case m := <-messages:
+			case m, ok := <-messages:
+				if !ok {
+					return
+				}
 				action(m)
 			case <-closer:
 				return
 			}
 		}
-	}, sub, unsub)
+	}()
 	subsc := (subscription)(closer)
 	return happened, &subsc
 }
@@ -87,39 +285,111 @@ func (this Channel) subscribe(action func(string), sub, unsub string) (startSign
 //It returns a channel that allows you to know when the channel has started succesfully listening, 
 //and a way to signal when you're done listening
 func (this Channel) Subscribe(action func(string)) (startSignal <-chan nothing, finishSignaler io.Closer) {
-	return this.subscribe(action, "subscribe", "unsubscribe")
+	return this.subscribe(payloadOnly(action), "subscribe")
 }
 
 //PatternSubscribe calls the specified function whenever a message along any of the channels that fit the pattern is published.
 //It returns a channel that allows you to know when the channel has started succesfully listening,
 //and a way to signal when you're done listening
 func (this Channel) PatternSubscribe(action func(string)) (startSignal <-chan nothing, finishSignaler io.Closer) {
-	return this.subscribe(action, "psubscribe", "punsubscribe")
+	return this.subscribe(payloadOnly(action), "psubscribe")
 }
 
-func (this Channel) blockingSubscription(subscription func(<-chan string), sub, unsub string) {
-	this.client.useNewConnection(func(conn *Connection) {
-		<-NilCommand(conn, this.args(sub)...)
+//SubscribeMessages is like Subscribe, but calls the specified function with everything that arrives on the subscription -
+//each published Message (along with the channel it was published on), and each subscription confirmation
+func (this Channel) SubscribeMessages(action func(Message)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.subscribe(action, "subscribe")
+}
 
-		defer func() {
-			<-NilCommand(conn, this.args(unsub)...)
-		}()
+//PatternSubscribeMessages is like PatternSubscribe, but calls the specified function with everything that arrives on the subscription,
+//which lets you tell which of the channels that fit the pattern each Message was published on
+func (this Channel) PatternSubscribeMessages(action func(Message)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.subscribe(action, "psubscribe")
+}
+
+//ShardSubscribe calls the specified function whenever a message is published on this shard channel (with ShardPublish).
+//It returns a channel that allows you to know when the channel has started succesfully listening,
+//and a way to signal when you're done listening
+func (this Channel) ShardSubscribe(action func(string)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.subscribe(payloadOnly(action), "ssubscribe")
+}
+
+//ShardSubscribeMessages is like ShardSubscribe, but calls the specified function with everything that arrives on the subscription
+func (this Channel) ShardSubscribeMessages(action func(Message)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.subscribe(action, "ssubscribe")
+}
 
-		subscription(messageLoop(conn, this.client.fErrCallback))
-		return
-	})
+func (this Channel) blockingSubscription(subscription func(<-chan Message), sub string) {
+	messages := make(chan Message, this.buffer.capacity())
+	done := make(chan nothing)
+	defer close(done)
+	go this.listen(messages, done, nil, sub)
+	subscription(messages)
 }
 
 //BlockingSubscription sends a message through a go channel whenever a message has been published on this redis channel. 
 //When the function terminates, the subscription is canceled
 func (this Channel) BlockingSubscription(subscription func(<-chan string)) {
-	this.blockingSubscription(subscription, "subscribe", "unsubscribe")
+	this.blockingSubscription(payloadChannel(subscription), "subscribe")
 }
 
 //BlockingPatternSubscription sends a message through a go channel whenever a message is published on any redis channel that fits the pattern.
 //When the function terminates, the subscription is canceled
 func (this Channel) BlockingPatternSubscription(subscription func(<-chan string)) {
-	this.blockingSubscription(subscription, "psubscribe", "punsubscribe")
+	this.blockingSubscription(payloadChannel(subscription), "psubscribe")
+}
+
+//BlockingMessageSubscription is like BlockingSubscription, but sends back everything that arrives on the subscription as a Message
+func (this Channel) BlockingMessageSubscription(subscription func(<-chan Message)) {
+	this.blockingSubscription(subscription, "subscribe")
+}
+
+//BlockingPatternMessageSubscription is like BlockingPatternSubscription, but sends back everything that arrives on the subscription as a Message
+func (this Channel) BlockingPatternMessageSubscription(subscription func(<-chan Message)) {
+	this.blockingSubscription(subscription, "psubscribe")
+}
+
+//BlockingShardSubscription sends a message through a go channel whenever a message has been published on this shard channel.
+//When the function terminates, the subscription is canceled
+func (this Channel) BlockingShardSubscription(subscription func(<-chan string)) {
+	this.blockingSubscription(payloadChannel(subscription), "ssubscribe")
+}
+
+//BlockingShardMessageSubscription is like BlockingShardSubscription, but sends back everything that arrives on the subscription as a Message
+func (this Channel) BlockingShardMessageSubscription(subscription func(<-chan Message)) {
+	this.blockingSubscription(subscription, "ssubscribe")
+}
+
+//payloadOnly adapts a function that only cares about the payload of each published message
+func payloadOnly(action func(string)) func(Message) {
+	return func(m Message) {
+		if m.IsMessage() {
+			action(m.Payload)
+		}
+	}
+}
+
+//payloadChannel adapts a subscription that only cares about the payload of each published message
+func payloadChannel(subscription func(<-chan string)) func(<-chan Message) {
+	return func(messages <-chan Message) {
+		payloads := make(chan string)
+		done := make(chan nothing)
+		defer close(done)
+		go func() {
+			defer close(payloads)
+			for m := range messages {
+				if !m.IsMessage() {
+					continue
+				}
+				select {
+				case payloads <- m.Payload:
+				case <-done:
+					return
+				}
+			}
+		}()
+		subscription(payloads)
+	}
 }
 
 //Publish publishes a message on this channel.
@@ -128,6 +398,36 @@ func (this Channel) Publish(message string) <-chan int {
 	return IntCommand(this, this.args("publish", message)...)
 }
 
+//SPUBLISH command -
+//ShardPublish publishes a message on this shard channel, which only goes to the node that holds the channel's slot when redis is running as a cluster.
+//Use ShardSubscribe, ShardSubscribeMessages, or BlockingShardSubscription to receive the published message
+func (this Channel) ShardPublish(message string) <-chan int {
+	return IntCommand(this, this.args("spublish", message)...)
+}
+
+//PUBSUB NUMSUB command -
+//SubscriberCount returns the number of clients subscribed to this channel (not counting pattern subscriptions)
+func (this Channel) SubscriberCount() <-chan int {
+	return this.count(pubsubCounts(this, "NUMSUB", []string{this.key}))
+}
+
+//PUBSUB SHARDNUMSUB command -
+//ShardSubscriberCount returns the number of clients subscribed to this shard channel
+func (this Channel) ShardSubscriberCount() <-chan int {
+	return this.count(pubsubCounts(this, "SHARDNUMSUB", []string{this.key}))
+}
+
+func (this Channel) count(in <-chan map[string]int) <-chan int {
+	out := make(chan int, 1)
+	go func() {
+		defer close(out)
+		if counts, ok := <-in; ok {
+			out <- counts[this.key]
+		}
+	}()
+	return out
+}
+
 //Use allows you to use this key on a different executor
 func (this Channel) Use(e SafeExecutor) Channel {
 	this.Key.client = e
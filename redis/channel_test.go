@@ -70,3 +70,84 @@ func TestChannels(t *testing.T) {
 	}
 
 }
+
+func TestChannelMessages(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	messages := make(chan Message, 8)
+	start, closer := r.Channel("Test_Channel_Messages:*").PatternSubscribeMessages(func(m Message) {
+		messages <- m
+	})
+	<-start
+
+	<-r.Channel("Test_Channel_Messages:A").Publish("Test")
+
+	expected := []Message{
+		{Kind: KindPatternSubscribe, Pattern: "Test_Channel_Messages:*", Count: 1},
+		{Kind: KindPatternMessage, Channel: "Test_Channel_Messages:A", Pattern: "Test_Channel_Messages:*", Payload: "Test"},
+	}
+	for _, e := range expected {
+		select {
+		case m := <-messages:
+			if m != e {
+				t.Error("Should have received", e, "not", m)
+			}
+		case <-time.After(time.Second):
+			t.Error("Should have received", e)
+		}
+	}
+	closer.Close()
+}
+
+func TestChannelReconnect(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	lost := make(chan string, 4)
+	r.SetErrorCallback(func(e error, s string) {
+		lost <- s
+	})
+
+	messages := make(chan Message, 8)
+	channel := r.Channel("Test_Channel_Reconnect")
+	start, closer := channel.SubscribeMessages(func(m Message) {
+		messages <- m
+	})
+	<-start
+	<-messages
+
+	if res := <-r.Server().KillClients(KillFilter{Type: "pubsub"}); res < 1 {
+		t.Fatal("Should have killed the subscription connection, not", res)
+	}
+	select {
+	case res := <-lost:
+		if res != "Lost subscription to Test_Channel_Reconnect" {
+			t.Error("Should have reported the lost subscription, not", res)
+		}
+	case <-time.After(time.Second):
+		t.Error("Should have reported the lost subscription")
+	}
+
+	for _, kind := range []string{KindSubscribe, KindReconnect} {
+		select {
+		case m := <-messages:
+			if m.Kind != kind {
+				t.Error("Should have been sent", kind, "not", m)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Should have been sent", kind)
+		}
+	}
+
+	<-channel.Publish("A")
+	select {
+	case m := <-messages:
+		if m.Payload != "A" {
+			t.Error("Should have received the message after reconnecting, not", m)
+		}
+	case <-time.After(time.Second):
+		t.Error("Should have received the message after reconnecting")
+	}
+	closer.Close()
+}
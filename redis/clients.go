@@ -0,0 +1,239 @@
+package redis
+
+import (
+	"strings"
+	"time"
+)
+
+//ClientInfo describes a single connection to the server, as returned by CLIENT LIST and CLIENT INFO
+type ClientInfo struct {
+	ID           int
+	Address      string
+	LocalAddress string
+	Name         string
+	User         string
+
+	//Age is how long the connection has been open, and Idle is how long it has been since it last issued a command
+	Age  time.Duration
+	Idle time.Duration
+
+	//Flags holds the CLIENT LIST flags of the connection (e.g. "N" for a normal client, "S" for a replica, "P" for a pub/sub subscriber)
+	Flags string
+
+	Database      int
+	Subscriptions int
+	Patterns      int
+
+	//LastCommand is the last command the connection ran (e.g. "client|list")
+	LastCommand string
+
+	//Fields holds every field that redis sent back, including the ones without their own member above
+	Fields map[string]string
+}
+
+func parseClientInfo(line string) ClientInfo {
+	info := ClientInfo{Fields: make(map[string]string)}
+	for _, field := range strings.Fields(line) {
+		if name, value, found := strings.Cut(field, "="); found {
+			info.Fields[name] = value
+		}
+	}
+	seconds := func(name string) time.Duration {
+		s, _ := atoi(info.Fields[name])
+		return time.Duration(s) * time.Second
+	}
+
+	info.ID, _ = atoi(info.Fields["id"])
+	info.Address = info.Fields["addr"]
+	info.LocalAddress = info.Fields["laddr"]
+	info.Name = info.Fields["name"]
+	info.User = info.Fields["user"]
+	info.Age = seconds("age")
+	info.Idle = seconds("idle")
+	info.Flags = info.Fields["flags"]
+	info.Database, _ = atoi(info.Fields["db"])
+	info.Subscriptions, _ = atoi(info.Fields["sub"])
+	info.Patterns, _ = atoi(info.Fields["psub"])
+	info.LastCommand = info.Fields["cmd"]
+	return info
+}
+
+func clientListChannel(in <-chan string) <-chan []ClientInfo {
+	out := make(chan []ClientInfo, 1)
+	go func() {
+		defer close(out)
+		if text, ok := <-in; ok {
+			clients := []ClientInfo{}
+			for _, line := range strings.Split(text, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					clients = append(clients, parseClientInfo(line))
+				}
+			}
+			out <- clients
+		}
+	}()
+	return out
+}
+
+//CLIENT LIST command -
+//Clients returns every connection to the server
+func (this Server) Clients() <-chan []ClientInfo {
+	return clientListChannel(StringCommand(this.client, "CLIENT", "LIST"))
+}
+
+//CLIENT LIST TYPE command -
+//ClientsOfType returns every connection of a given type (normal, master, replica or pubsub)
+func (this Server) ClientsOfType(clientType string) <-chan []ClientInfo {
+	return clientListChannel(StringCommand(this.client, "CLIENT", "LIST", "TYPE", clientType))
+}
+
+//CLIENT LIST ID command -
+//ClientsWithIDs returns the connections with the given server-side IDs (e.g. the ones from Client.ConnectionIDs)
+func (this Server) ClientsWithIDs(ids ...int) <-chan []ClientInfo {
+	return clientListChannel(StringCommand(this.client, append([]string{"CLIENT", "LIST", "ID"}, intsToStrings(ids)...)...))
+}
+
+//CLIENT INFO command -
+//CurrentClient returns the connection that ran the command.
+//When used straight from a Client, this could be any connection in the pool
+func (this Server) CurrentClient() <-chan ClientInfo {
+	out := make(chan ClientInfo, 1)
+	in := StringCommand(this.client, "CLIENT", "INFO")
+	go func() {
+		defer close(out)
+		if text, ok := <-in; ok {
+			out <- parseClientInfo(strings.TrimSpace(text))
+		}
+	}()
+	return out
+}
+
+//CLIENT ID command -
+//ClientID returns the server-side ID of the connection that ran the command.
+//When used straight from a Client, this could be any connection in the pool
+func (this Server) ClientID() <-chan int {
+	return IntCommand(this.client, "CLIENT", "ID")
+}
+
+//KillFilter picks out which connections CLIENT KILL closes; only connections that fit every field that has been set are closed
+type KillFilter struct {
+	//ID only closes the connection with this server-side ID
+	ID int
+
+	//Type only closes connections of this type (normal, master, replica or pubsub)
+	Type string
+
+	//User only closes connections authenticated as this user
+	User string
+
+	//Address and LocalAddress only close the connection with this remote or local address (ip:port)
+	Address      string
+	LocalAddress string
+
+	//MaxAge only closes connections that have been open for longer than this
+	MaxAge time.Duration
+
+	//IncludeSelf allows the connection running the command to close itself
+	IncludeSelf bool
+}
+
+func (this KillFilter) args() []string {
+	result := []string{}
+	if this.ID != 0 {
+		result = append(result, "ID", itoa(this.ID))
+	}
+	if this.Type != "" {
+		result = append(result, "TYPE", this.Type)
+	}
+	if this.User != "" {
+		result = append(result, "USER", this.User)
+	}
+	if this.Address != "" {
+		result = append(result, "ADDR", this.Address)
+	}
+	if this.LocalAddress != "" {
+		result = append(result, "LADDR", this.LocalAddress)
+	}
+	if this.MaxAge > 0 {
+		result = append(result, "MAXAGE", itoa(int(this.MaxAge/time.Second)))
+	}
+	if this.IncludeSelf {
+		result = append(result, "SKIPME", "no")
+	}
+	return result
+}
+
+//CLIENT KILL command -
+//KillClients closes every connection that fits the filter;
+//returns the number of connections closed
+func (this Server) KillClients(filter KillFilter) <-chan int {
+	return IntCommand(this.client, append([]string{"CLIENT", "KILL"}, filter.args()...)...)
+}
+
+//CLIENT PAUSE command -
+//PauseClients stops the server from running commands from any normal or pub/sub connection for a while (e.g. during a failover)
+func (this Server) PauseClients(duration time.Duration) <-chan nothing {
+	return NilCommand(this.client, "CLIENT", "PAUSE", itoa(int(duration/time.Millisecond)), "ALL")
+}
+
+//CLIENT PAUSE WRITE command -
+//PauseWrites stops the server from running commands that could change data for a while, while still running reads
+func (this Server) PauseWrites(duration time.Duration) <-chan nothing {
+	return NilCommand(this.client, "CLIENT", "PAUSE", itoa(int(duration/time.Millisecond)), "WRITE")
+}
+
+//CLIENT UNPAUSE command -
+//UnpauseClients ends a pause early
+func (this Server) UnpauseClients() <-chan nothing {
+	return NilCommand(this.client, "CLIENT", "UNPAUSE")
+}
+
+//CLIENT UNBLOCK command -
+//UnblockClient wakes up a connection that is waiting on a blocking command (such as BLPOP);
+//if withError is true, the blocking command fails instead of timing out.
+//Returns whether or not the connection was blocked
+func (this Server) UnblockClient(id int, withError bool) <-chan bool {
+	mode := "TIMEOUT"
+	if withError {
+		mode = "ERROR"
+	}
+	return BoolCommand(this.client, "CLIENT", "UNBLOCK", itoa(id), mode)
+}
+
+//CLIENT ID command -
+//ConnectionIDs returns the server-side ID of every connection in the pool, indexed by the ID the Client gave the connection.
+//The server-side IDs can be used to look the connections up with Server.ClientsWithIDs, or to close them with Server.KillClients.
+//(Warning - this is *not* a lightweight function - it waits until every connection in the pool is free)
+func (this *Client) ConnectionIDs() <-chan map[int]int {
+	out := make(chan map[int]int, 1)
+	go func() {
+		defer close(out)
+		ids := make(map[int]int)
+		this.useAllConnections(func(conn *Connection) {
+			if id, ok := <-IntCommand(conn, "CLIENT", "ID"); ok {
+				ids[conn.id] = id
+			}
+		})
+		out <- ids
+	}()
+	return out
+}
+
+//CLIENT NO-EVICT command -
+//SetNoEvict decides whether or not every connection in the pool is protected from being closed when the server runs low on memory for clients.
+//(Warning - this is *not* a lightweight function - it waits until every connection in the pool is free)
+func (this *Client) SetNoEvict(on bool) <-chan nothing {
+	out := make(chan nothing, 1)
+	mode := "OFF"
+	if on {
+		mode = "ON"
+	}
+	go func() {
+		defer close(out)
+		this.useAllConnections(func(conn *Connection) {
+			<-NilCommand(conn, "CLIENT", "NO-EVICT", mode)
+		})
+		out <- nothing{}
+	}()
+	return out
+}
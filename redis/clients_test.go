@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClients(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Server()
+
+	ids := <-r.ConnectionIDs()
+	if len(ids) != DefaultConfiguration().ConnectionCount {
+		t.Error("Should have an ID for every connection in the pool, not", len(ids))
+	}
+	serverIDs := make([]int, 0, len(ids))
+	for _, id := range ids {
+		serverIDs = append(serverIDs, id)
+	}
+
+	if res := <-s.ClientsWithIDs(serverIDs...); len(res) == 0 || len(res) != len(ids) {
+		t.Error("Should have found every connection in the pool, not", len(res))
+	} else if res[0].Address == "" || res[0].Fields["id"] != itoa(res[0].ID) {
+		t.Error("Client details weren't parsed properly -", res[0])
+	}
+	if res := <-s.Clients(); len(res) < len(ids) {
+		t.Error("Should have listed at least as many clients as are in the pool, not", len(res))
+	}
+
+	if current := <-s.CurrentClient(); current.ID == 0 || current.Name != "" {
+		t.Error("Should have gotten the details of the current connection, not", current)
+	}
+	if res := <-s.ClientID(); res == 0 {
+		t.Error("Should have gotten a client ID")
+	}
+
+	other := GetRedis(t)
+	defer other.Close()
+	otherIDs := <-other.ConnectionIDs()
+	blocked := other.List("Test_Clients_List").BlockUntilLeftPopWithTimeout(10)
+	time.Sleep(50 * time.Millisecond)
+	if res := <-s.ClientsOfType("normal"); len(res) <= len(ids) {
+		t.Error("Should have seen the connections of the other client as well")
+	}
+	unblocked := false
+	for _, id := range otherIDs {
+		if <-s.UnblockClient(id, false) {
+			unblocked = true
+		}
+	}
+	if !unblocked {
+		t.Error("Should have unblocked the other client")
+	}
+	<-blocked
+
+	<-r.SetNoEvict(true)
+	<-r.SetNoEvict(false)
+
+	<-s.PauseWrites(time.Second)
+	<-s.UnpauseClients()
+
+	killed := 0
+	for _, id := range otherIDs {
+		killed += <-s.KillClients(KillFilter{ID: id})
+	}
+	if killed != len(otherIDs) {
+		t.Error("Should have killed every connection of the other client, not", killed)
+	}
+}
+
+func TestConcurrentConnectionIDs(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	//both calls need the whole pool, so neither may hold on to part of it while waiting for the rest
+	first, second := r.ConnectionIDs(), r.ConnectionIDs()
+	noEvict := r.SetNoEvict(false)
+	for _, ids := range []<-chan map[int]int{first, second} {
+		select {
+		case res := <-ids:
+			if len(res) != DefaultConfiguration().ConnectionCount {
+				t.Error("Should have an ID for every connection in the pool, not", len(res))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the connection IDs - the calls are holding on to parts of the pool")
+		}
+	}
+	select {
+	case <-noEvict:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for NO-EVICT to be set")
+	}
+}
@@ -8,10 +8,56 @@ import (
 //	"bufio"
 )
 
-//a response either has a value, or a list of subresponses (which themselves usually have values, but occasionally subresponses)
+//a response either has a value, or a list of subresponses (which themselves usually have values, but occasionally subresponses).
+//Subresponses can also be errors, when Redis reports a failure for one item of a multi-bulk reply (e.g. a single command within EXEC)
 type response struct {
 	val          string
 	subresponses []*response
+	err          error
+}
+
+//fields treats a multi-bulk response as a list of alternating field names and values
+func (this *response) fields() map[string]*response {
+	result := make(map[string]*response)
+	if this == nil {
+		return result
+	}
+	for i := 0; i+1 < len(this.subresponses); i += 2 {
+		if this.subresponses[i] != nil {
+			result[this.subresponses[i].val] = this.subresponses[i+1]
+		}
+	}
+	return result
+}
+
+//values returns the values of all of the subresponses of a multi-bulk response
+func (this *response) values() []string {
+	if this == nil {
+		return nil
+	}
+	result := make([]string, len(this.subresponses))
+	for i, sub := range this.subresponses {
+		if sub != nil {
+			result[i] = sub.val
+		}
+	}
+	return result
+}
+
+//value returns the value of a response, even if there wasn't one
+func (this *response) value() string {
+	if this == nil {
+		return ""
+	}
+	return this.val
+}
+
+//ReplyError is an error that Redis itself sent back in response to a command,
+//as opposed to an error that happened while trying to communicate with Redis
+type ReplyError string
+
+func (this ReplyError) Error() string {
+	return string(this)
 }
 
 const (
@@ -32,6 +78,11 @@ type command interface {
 	callback() func(*response) error
 }
 
+//a command that knows how to recover from an error can implement retrier to be given a chance to send a different command instead
+type retrier interface {
+	retry(error) command
+}
+
 //Anything that can execute a command is an Executor
 type Executor interface {
 	Execute(command)
@@ -79,7 +130,7 @@ func buildCommand(arguments []string) ([]byte, error) {
 
 func getResponse(conn io.Reader) (*response, error) {
 	var buffer [1]byte
-	_, err := conn.Read(buffer[:])
+	_, err := io.ReadFull(conn, buffer[:])
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +141,7 @@ func getResponse(conn io.Reader) (*response, error) {
 			return nil, err
 		}
 
-		return nil, errors.New(errString)
+		return nil, ReplyError(errString)
 	case isStatus, isInt:
 		return getStringResponse(conn)
 	case isBulk:
@@ -100,25 +151,18 @@ func getResponse(conn io.Reader) (*response, error) {
 	default:
 		return nil, errors.New("Unknown Data Type:'" + string(buffer[0:1]) + "'")
 	}
-	return nil, errors.New("unexpected data")
 }
 
 func getString(conn io.Reader) (string, error) {
-	var buffer [bufferSize]byte
-	j := -len(delimiter)
-	i := 0
-	for {
-		if j >= 0 && bytes.Equal(buffer[j:i], delimiter) {
-			return string(buffer[:j]), nil
-		}
-		if i >= bufferSize {
-			return "", errors.New("Short Buffer - " + string(buffer[:]))
+	buffer := make([]byte, 0, bufferSize)
+	var next [1]byte
+	for !bytes.HasSuffix(buffer, delimiter) {
+		if _, err := io.ReadFull(conn, next[:]); err != nil {
+			return "", err
 		}
-		conn.Read(buffer[i : i+1])
-		i++
-		j++
+		buffer = append(buffer, next[0])
 	}
-	return string(buffer[:]), nil
+	return string(buffer[:len(buffer)-len(delimiter)]), nil
 }
 
 func getStringResponse(conn io.Reader) (*response, error) {
@@ -146,7 +190,7 @@ func getBulk(conn io.Reader) (*response, error) {
 	}
 
 	b := make([]byte, strlen+len(delimiter))
-	i, err := conn.Read(b)
+	i, err := io.ReadFull(conn, b)
 	if err != nil {
 		//the read should be successful
 		return nil, err
@@ -185,7 +229,10 @@ func getMultiBulk(conn io.Reader) (*response, error) {
 	for iResponse := 0; iResponse < int(cResponses); iResponse++ {
 		var err error
 		r.subresponses[iResponse], err = getResponse(conn)
-		if err != nil {
+		if replyErr, ok := err.(ReplyError); ok {
+			//an error for a single item shouldn't stop us from reading the rest of the reply
+			r.subresponses[iResponse] = &response{err: replyErr}
+		} else if err != nil {
 			return nil, err
 		}
 	}
@@ -289,7 +336,7 @@ func (this floatCommand) callback() func(*response) error {
 		defer close(this.output)
 		if r != nil {
 			f, err := atof(r.val)
-			if err != nil {
+			if err == nil {
 				this.output <- f
 			}
 		}
@@ -489,3 +536,36 @@ func (this nilCommand) callback() func(*response) error {
 		return nil
 	}
 }
+
+/*
+
+responseCommand - the command type used when the response is too complicated for the other command types,
+and needs to be decoded by whatever issued the command
+
+*/
+
+type responseCommand struct {
+	args   []string
+	output chan<- *response
+}
+
+//responseChannel executes the command specified by the arguments specified, and gives back the response as is
+func responseChannel(e Executor, args ...string) <-chan *response {
+	c := make(chan *response, 1)
+	e.Execute(responseCommand{args, c})
+	return c
+}
+
+func (this responseCommand) arguments() []string {
+	return this.args
+}
+
+func (this responseCommand) callback() func(*response) error {
+	return func(r *response) error {
+		defer close(this.output)
+		if r != nil {
+			this.output <- r
+		}
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLongStatusLines(t *testing.T) {
+	long := strings.Repeat("x", 4*bufferSize)
+
+	r, err := getResponse(strings.NewReader("+" + long + "\r\n"))
+	if err != nil || r.value() != long {
+		t.Error("A status line longer than the buffer should be read in full, got", len(r.value()), "bytes and", err)
+	}
+
+	_, err = getResponse(strings.NewReader("-ERR " + long + "\r\n"))
+	if replyErr, ok := err.(ReplyError); !ok || string(replyErr) != "ERR "+long {
+		t.Error("An error line longer than the buffer should be read in full, got", err)
+	}
+
+	if _, err := getResponse(strings.NewReader("+" + long)); err == nil {
+		t.Error("A status line that never ends should fail")
+	}
+}
+
+func TestShortReads(t *testing.T) {
+	bulk := strings.Repeat("y", 10000)
+	reply := "*3\r\n$" + itoa(len(bulk)) + "\r\n" + bulk + "\r\n:42\r\n$-1\r\n"
+
+	for name, reader := range map[string]io.Reader{
+		"one byte at a time": iotest.OneByteReader(strings.NewReader(reply)),
+		"half at a time":     iotest.HalfReader(strings.NewReader(reply)),
+		"with empty reads":   iotest.DataErrReader(strings.NewReader(reply)),
+	} {
+		r, err := getResponse(reader)
+		if err != nil {
+			t.Error(name+": the reply should be read in full, got", err)
+			continue
+		}
+		if values := r.values(); len(values) != 3 || values[0] != bulk || values[1] != "42" || r.subresponses[2] != nil {
+			t.Error(name+": the reply was read wrong, got", len(values), "values")
+		}
+	}
+
+	if _, err := getResponse(strings.NewReader("$10\r\nshort")); err == nil {
+		t.Error("A bulk reply that is cut short should fail")
+	}
+}
+
+func TestFloatCommand(t *testing.T) {
+	out := make(chan float64, 1)
+	floatCommand{nil, out}.callback()(&response{val: "2.5"})
+	if f, ok := <-out; !ok || f != 2.5 {
+		t.Error("Should have sent back 2.5, not", f, ok)
+	}
+
+	out = make(chan float64, 1)
+	floatCommand{nil, out}.callback()(&response{val: "not a number"})
+	if f, ok := <-out; ok {
+		t.Error("Should not send back anything for a reply that isn't a float, not", f)
+	}
+
+	r := GetRedis(t)
+	defer r.Close()
+	f := r.Float("Test_Float_Command")
+	<-f.Set(1.25)
+	if res, ok := <-FloatCommand(r, "GET", "Test_Float_Command"); !ok || res != 1.25 {
+		t.Error("Should have gotten 1.25 back from redis, not", res, ok)
+	}
+	<-f.Delete()
+}
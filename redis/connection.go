@@ -26,6 +26,11 @@ func (this Connection) input(command command) error {
 func (this Connection) output(command command) error {
 	res, err := getResponse(this)
 	if err != nil {
+		if r, ok := command.(retrier); ok {
+			if next := r.retry(err); next != nil {
+				return this.execute(next)
+			}
+		}
 		command.callback()(nil)
 		return err
 	}
@@ -39,16 +44,19 @@ func (this Connection) Error(e error, c command) {
 	this.client.errCallback(e, strings.Join(c.arguments(), " "))
 }
 
-//Execute allows a command to be executed on a specific connection
-func (this Connection) Execute(command command) {
+func (this Connection) execute(command command) error {
 	err := this.input(command)
 	if err != nil {
-		this.Error(err, command)
-		return
+		command.callback()(nil)
+		return err
 	}
 
-	err = this.output(command)
-	if err != nil {
+	return this.output(command)
+}
+
+//Execute allows a command to be executed on a specific connection
+func (this Connection) Execute(command command) {
+	if err := this.execute(command); err != nil {
 		this.Error(err, command)
 	}
 }
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"strconv"
+	"time"
 )
 
 func ftoa(f float64) string {
@@ -105,6 +106,17 @@ func stringChannel(in <-chan []string, index int) <-chan string {
 	return out
 }
 
+func boolChannel(in <-chan []string, index int) <-chan bool {
+	out := make(chan bool, 1)
+	go func() {
+		defer close(out)
+		if slice, ok := <-in; ok && index < len(slice) {
+			out <- slice[index] == "1"
+		}
+	}()
+	return out
+}
+
 func intChannel(in <-chan []string, index int) <-chan int {
 	out := make(chan int, 1)
 	go func() {
@@ -131,6 +143,18 @@ func floatChannel(in <-chan []string, index int) <-chan float64 {
 	return out
 }
 
+//durationChannel converts a number of units into a duration; negative numbers are how redis says there isn't one, so they get skipped
+func durationChannel(in <-chan int, unit time.Duration) <-chan time.Duration {
+	out := make(chan time.Duration, 1)
+	go func() {
+		defer close(out)
+		if i, ok := <-in; ok && i >= 0 {
+			out <- time.Duration(i) * unit
+		}
+	}()
+	return out
+}
+
 func maybeIntsChannel(in <-chan []*string) <-chan []*int {
 	out := make(chan []*int, 1)
 	go func() {
@@ -186,6 +210,23 @@ func stringfloatMapChannel(in <-chan map[string]string) <-chan map[string]float6
 	return out
 }
 
+func stringintMapChannel(in <-chan map[string]string) <-chan map[string]int {
+	out := make(chan map[string]int, 1)
+	go func() {
+		defer close(out)
+		if strings, ok := <-in; ok {
+			result := make(map[string]int, len(strings))
+			for k, v := range strings {
+				if i, err := atoi(v); err == nil {
+					result[k] = i
+				}
+			}
+			out <- result
+		}
+	}()
+	return out
+}
+
 func intfloatMapChannel(in <-chan map[string]string) <-chan map[int]float64 {
 	out := make(chan map[int]float64, 1)
 	go func() {
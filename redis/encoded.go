@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"encoding/json"
+	"io"
+)
+
+//A Codec turns values into message payloads and back again
+type Codec interface {
+	Encode(value interface{}) (string, error)
+
+	//Decode fills in the value that target points to
+	Decode(payload string, target interface{}) error
+}
+
+//JSONCodec encodes values as JSON (with the encoding/json package)
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (this jsonCodec) Encode(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+func (this jsonCodec) Decode(payload string, target interface{}) error {
+	return json.Unmarshal([]byte(payload), target)
+}
+
+//EncodedChannel is a Channel that publishes Go values of a single type (encoded with a Codec), and decodes them again when they are received
+type EncodedChannel[T any] struct {
+	channel Channel
+	codec   Codec
+	onError func(error, Message)
+}
+
+//Encoded publishes and receives values of type T on the channel through the specified codec (such as JSONCodec),
+//e.g. Encoded[Event](client.Channel("events"), JSONCodec)
+func Encoded[T any](channel Channel, codec Codec) EncodedChannel[T] {
+	return EncodedChannel[T]{
+		channel: channel,
+		codec:   codec,
+	}
+}
+
+//OnDecodeError calls the specified function whenever a received message can't be decoded.
+//Without it, the error is sent to the Client's error callback if one has been set, and ignored otherwise.
+//The message is skipped either way, and the subscription carries on
+func (this EncodedChannel[T]) OnDecodeError(handler func(error, Message)) EncodedChannel[T] {
+	this.onError = handler
+	return this
+}
+
+//Publish encodes the value and publishes it on this channel.
+//If the value can't be encoded, the error is sent to the error callback and nothing is published
+func (this EncodedChannel[T]) Publish(value T) <-chan int {
+	payload, err := this.codec.Encode(value)
+	if err != nil {
+		this.channel.Key.client.errCallback(err, "Encoding message for "+this.channel.key)
+		out := make(chan int)
+		close(out)
+		return out
+	}
+	return this.channel.Publish(payload)
+}
+
+//Subscribe calls the handler with each value that is published on this channel.
+//It returns a channel that allows you to know when the channel has started succesfully listening,
+//and a way to signal when you're done listening
+func (this EncodedChannel[T]) Subscribe(handler func(T)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.channel.SubscribeMessages(this.decoder(handler))
+}
+
+//PatternSubscribe is like Subscribe, but decodes the values published on any of the channels that fit the pattern
+func (this EncodedChannel[T]) PatternSubscribe(handler func(T)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.channel.PatternSubscribeMessages(this.decoder(handler))
+}
+
+//decoder decodes each published message into a new value, and hands it to the handler
+func (this EncodedChannel[T]) decoder(handler func(T)) func(Message) {
+	return func(m Message) {
+		if !m.IsMessage() {
+			return
+		}
+		var value T
+		if err := this.codec.Decode(m.Payload, &value); err != nil {
+			//a message that can't be decoded shouldn't bring down the subscription (the default error callback panics)
+			if this.onError != nil {
+				this.onError(err, m)
+			} else if this.channel.client.fErrCallback != nil {
+				this.channel.client.errCallback(err, "Decoding message from "+m.Channel)
+			}
+			return
+		}
+		handler(value)
+	}
+}
+
+//Channel returns the underlying channel, for publishing or receiving raw payloads
+func (this EncodedChannel[T]) Channel() Channel {
+	return this.channel
+}
+
+//Use allows you to publish on a different executor
+func (this EncodedChannel[T]) Use(e SafeExecutor) EncodedChannel[T] {
+	this.channel = this.channel.Use(e)
+	return this
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+type encodedTestEvent struct {
+	Name  string
+	Count int
+}
+
+func TestEncodedChannel(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	channel := Encoded[*encodedTestEvent](r.Channel("Test_Encoded_Channel"), JSONCodec)
+	events := make(chan encodedTestEvent, 1)
+	failures := make(chan Message, 1)
+
+	start, closer := channel.OnDecodeError(func(err error, m Message) {
+		failures <- m
+	}).Subscribe(func(event *encodedTestEvent) {
+		events <- *event
+	})
+	defer closer.Close()
+	<-start
+
+	channel.Channel().Publish("not json")
+	channel.Publish(&encodedTestEvent{Name: "Test", Count: 3})
+
+	select {
+	case m := <-failures:
+		if m.Payload != "not json" {
+			t.Error("The wrong message failed to decode:", m)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the decode error")
+	}
+	select {
+	case event := <-events:
+		if event.Name != "Test" || event.Count != 3 {
+			t.Error("Received the wrong event:", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the event")
+	}
+
+	//without a decode error handler or an error callback, messages that can't be decoded are skipped
+	r.SetErrorCallback(nil)
+	counts := make(chan map[string]int, 1)
+	counter := Encoded[map[string]int](r.Channel("Test_Encoded_Counts"), JSONCodec)
+	start, closer = counter.Subscribe(func(c map[string]int) {
+		counts <- c
+	})
+	defer closer.Close()
+	<-start
+	counter.Channel().Publish("not json")
+	counter.Publish(map[string]int{"a": 1})
+	select {
+	case c := <-counts:
+		if c["a"] != 1 {
+			t.Error("Received the wrong counts:", c)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the counts")
+	}
+}
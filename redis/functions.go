@@ -0,0 +1,218 @@
+package redis
+
+//RestorePolicy decides what happens to the function libraries already in redis when restoring a dump
+type RestorePolicy string
+
+const (
+	//RestoreAppend adds the dumped libraries, failing if any of them already exist
+	RestoreAppend RestorePolicy = "APPEND"
+
+	//RestoreReplace adds the dumped libraries, replacing any that already exist
+	RestoreReplace RestorePolicy = "REPLACE"
+
+	//RestoreFlush deletes every existing library before adding the dumped libraries
+	RestoreFlush RestorePolicy = "FLUSH"
+)
+
+//Functions encapsulates the commands that manage redis function libraries (requires redis 7).
+//See http://redis.io/topics/functions-intro for more information on redis functions
+type Functions struct {
+	client SafeExecutor
+}
+
+func newFunctions(client SafeExecutor) Functions {
+	return Functions{
+		client: client,
+	}
+}
+
+//FunctionLibrary describes a library of functions that has been loaded into redis
+type FunctionLibrary struct {
+	Name      string
+	Engine    string
+	Functions []FunctionDetails
+
+	//Code is only filled in when listing libraries with their code
+	Code string
+}
+
+//FunctionDetails describes a single function within a FunctionLibrary
+type FunctionDetails struct {
+	Name        string
+	Description string
+	Flags       []string
+}
+
+//FUNCTION LOAD command -
+//Load adds a library to redis; the library's name comes from the "#!lua name=..." line at the top of the code.
+//Fails if a library with the same name already exists;
+//returns the name of the library
+func (this Functions) Load(code string) <-chan string {
+	return StringCommand(this.client, "FUNCTION", "LOAD", code)
+}
+
+//FUNCTION LOAD REPLACE command -
+//Replace adds a library to redis, replacing the library with the same name if there is one (e.g. when deploying a new version);
+//returns the name of the library
+func (this Functions) Replace(code string) <-chan string {
+	return StringCommand(this.client, "FUNCTION", "LOAD", "REPLACE", code)
+}
+
+//FUNCTION DELETE command -
+//Delete removes a library and all of its functions
+func (this Functions) Delete(library string) <-chan nothing {
+	return NilCommand(this.client, "FUNCTION", "DELETE", library)
+}
+
+//FUNCTION LIST command -
+//List returns every library whose name fits the pattern (use "*" for all of them)
+func (this Functions) List(pattern string) <-chan []FunctionLibrary {
+	return functionLibrariesChannel(responseChannel(this.client, "FUNCTION", "LIST", "LIBRARYNAME", pattern))
+}
+
+//FUNCTION LIST WITHCODE command -
+//ListWithCode returns every library whose name fits the pattern, along with its code
+func (this Functions) ListWithCode(pattern string) <-chan []FunctionLibrary {
+	return functionLibrariesChannel(responseChannel(this.client, "FUNCTION", "LIST", "LIBRARYNAME", pattern, "WITHCODE"))
+}
+
+//FUNCTION DUMP command -
+//Dump returns a serialized copy of every library, which can be used with Restore
+func (this Functions) Dump() <-chan string {
+	return StringCommand(this.client, "FUNCTION", "DUMP")
+}
+
+//FUNCTION RESTORE command -
+//Restore loads libraries from a payload created by Dump
+func (this Functions) Restore(payload string, policy RestorePolicy) <-chan nothing {
+	return NilCommand(this.client, "FUNCTION", "RESTORE", payload, string(policy))
+}
+
+//FUNCTION FLUSH command -
+//Flush deletes every library
+func (this Functions) Flush() <-chan nothing {
+	return NilCommand(this.client, "FUNCTION", "FLUSH", "SYNC")
+}
+
+//FUNCTION FLUSH ASYNC command -
+//FlushAsync deletes every library, letting redis free up the memory in the background
+func (this Functions) FlushAsync() <-chan nothing {
+	return NilCommand(this.client, "FUNCTION", "FLUSH", "ASYNC")
+}
+
+//Function defines a function that has been registered by one of the libraries
+func (this Functions) Function(name string) Function {
+	return newFunction(this.client, name)
+}
+
+//Use allows you to use these commands on a different executor
+func (this Functions) Use(e SafeExecutor) Functions {
+	this.client = e
+	return this
+}
+
+func functionLibrariesChannel(in <-chan *response) <-chan []FunctionLibrary {
+	out := make(chan []FunctionLibrary, 1)
+	go func() {
+		defer close(out)
+		if r, ok := <-in; ok {
+			libraries := make([]FunctionLibrary, 0, len(r.subresponses))
+			for _, sub := range r.subresponses {
+				fields := sub.fields()
+				library := FunctionLibrary{
+					Name:   fields["library_name"].value(),
+					Engine: fields["engine"].value(),
+					Code:   fields["library_code"].value(),
+				}
+				if functions := fields["functions"]; functions != nil {
+					for _, f := range functions.subresponses {
+						details := f.fields()
+						library.Functions = append(library.Functions, FunctionDetails{
+							Name:        details["name"].value(),
+							Description: details["description"].value(),
+							Flags:       details["flags"].values(),
+						})
+					}
+				}
+				libraries = append(libraries, library)
+			}
+			out <- libraries
+		}
+	}()
+	return out
+}
+
+//Function is a function that has been registered by a redis function library
+type Function struct {
+	name   string
+	client SafeExecutor
+}
+
+func newFunction(client SafeExecutor, name string) Function {
+	return Function{
+		name:   name,
+		client: client,
+	}
+}
+
+//With sets up a call of this function, and uses the supplied objects as the keys the function operates on
+func (this Function) With(keys ...Keyed) *FunctionCall {
+	call := &FunctionCall{
+		function: this,
+		op:       "FCALL",
+		keys:     keyNames(keys),
+	}
+	call.callResult = callResult{call.execute}
+	return call
+}
+
+//Args sets up a call of this function without any keys, but with the supplied arguments
+func (this Function) Args(args ...string) *FunctionCall {
+	return this.With().Args(args...)
+}
+
+//Use allows you to call this function on a different executor
+func (this Function) Use(e SafeExecutor) Function {
+	this.client = e
+	return this
+}
+
+//FunctionCall keeps track of the keys and arguments that a function is being called with.
+//Use any of its Run or Get functions to call the function (with FCALL) and decode the result
+type FunctionCall struct {
+	callResult
+	function Function
+	op       string
+	keys     []string
+	args     []string
+}
+
+//Args adds to the arguments that the function will be called with
+func (this *FunctionCall) Args(args ...string) *FunctionCall {
+	this.args = append(this.args, args...)
+	return this
+}
+
+//IntArgs adds integers to the arguments that the function will be called with
+func (this *FunctionCall) IntArgs(args ...int) *FunctionCall {
+	return this.Args(intsToStrings(args)...)
+}
+
+//FloatArgs adds floating point numbers to the arguments that the function will be called with
+func (this *FunctionCall) FloatArgs(args ...float64) *FunctionCall {
+	return this.Args(floatsToStrings(args)...)
+}
+
+//ReadOnly calls the function with FCALL_RO, which lets it run on read-only replicas.
+//The function must have been registered with the "no-writes" flag
+func (this *FunctionCall) ReadOnly() *FunctionCall {
+	this.op = "FCALL_RO"
+	return this
+}
+
+func (this *FunctionCall) execute(inner command) {
+	this.function.client.Execute(scriptCommand{
+		command: inner,
+		args:    callArguments(this.op, this.function.name, this.keys, this.args),
+	})
+}
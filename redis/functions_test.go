@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"testing"
+)
+
+const testLibrary = `#!lua name=testlib
+redis.register_function('testlib_push', function(keys, args)
+	return redis.call('RPUSH', keys[1], unpack(args))
+end)
+redis.register_function{
+	function_name='testlib_length',
+	callback=function(keys, args) return redis.call('LLEN', keys[1]) end,
+	flags={'no-writes'}
+}
+`
+
+func TestFunctions(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	f := r.Functions()
+	<-f.Flush()
+
+	if res := <-f.Load(testLibrary); res != "testlib" {
+		t.Fatal("Library should be named testlib, not", res)
+	}
+	if _, ok := <-f.Load(testLibrary); ok {
+		t.Error("Should not be able to load the same library twice")
+	}
+	if res := <-f.Replace(testLibrary); res != "testlib" {
+		t.Error("Should be able to replace the library, got", res)
+	}
+
+	libraries := <-f.List("test*")
+	if len(libraries) != 1 || libraries[0].Name != "testlib" || len(libraries[0].Functions) != 2 {
+		t.Fatal("Should have found testlib with 2 functions, not", libraries)
+	}
+	if libraries[0].Code != "" {
+		t.Error("Should not have gotten the code back")
+	}
+	libraries = <-f.ListWithCode("testlib")
+	if len(libraries) != 1 || libraries[0].Code != testLibrary {
+		t.Error("Should have gotten the code back")
+	}
+
+	l := r.Prefix("Test_Functions:").List("List")
+	<-l.Delete()
+
+	if res := <-f.Function("testlib_push").With(l).Args("A", "B").GetInt(); res != 2 {
+		t.Error("Should have pushed 2 items, not", res)
+	}
+	if res := <-f.Function("testlib_length").With(l).ReadOnly().GetInt(); res != 2 {
+		t.Error("Should have a length of 2, not", res)
+	}
+	if res := <-r.List("Test_Functions:List").GetFromRange(0, -1); len(res) != 2 || res[0] != "A" {
+		t.Error("Function should have used the prefixed key, got", res)
+	}
+
+	dump := <-f.Dump()
+	<-f.Delete("testlib")
+	if res := <-f.List("*"); len(res) != 0 {
+		t.Error("Library should have been deleted")
+	}
+	<-f.Restore(dump, RestoreFlush)
+	if res := <-f.List("*"); len(res) != 1 {
+		t.Error("Library should have been restored")
+	}
+
+	var length <-chan int
+	r.Transaction(func(e SafeExecutor) {
+		length = f.Use(e).Function("testlib_length").With(l).GetInt()
+	})
+	if res := <-length; res != 2 {
+		t.Error("Should have a length of 2 within a transaction, not", res)
+	}
+	<-f.Flush()
+}
@@ -0,0 +1,337 @@
+package redis
+
+//GeoUnit is the unit that distances are measured in
+type GeoUnit string
+
+//The units that redis understands
+const (
+	Meters     GeoUnit = "m"
+	Kilometers GeoUnit = "km"
+	Miles      GeoUnit = "mi"
+	Feet       GeoUnit = "ft"
+)
+
+//GeoPosition is a point on the earth
+type GeoPosition struct {
+	Longitude float64
+	Latitude  float64
+}
+
+//GeoLocation is a member of a Geo index, along with where it is
+type GeoLocation struct {
+	Member string
+	GeoPosition
+}
+
+//GeoAddOptions changes how members are added to a Geo index
+type GeoAddOptions struct {
+	//OnlyNew only adds new members, leaving the positions of existing members alone
+	OnlyNew bool
+
+	//OnlyExisting only moves existing members, without adding any new ones
+	OnlyExisting bool
+
+	//CountChanged makes the result count the members that were moved, as well as the ones that were added
+	CountChanged bool
+}
+
+func (this GeoAddOptions) args() []string {
+	result := []string{}
+	if this.OnlyNew {
+		result = append(result, "NX")
+	}
+	if this.OnlyExisting {
+		result = append(result, "XX")
+	}
+	if this.CountChanged {
+		result = append(result, "CH")
+	}
+	return result
+}
+
+//GeoResult is a single member found by a GeoSearch.
+//Distance and Position are only filled in if they were asked for with WithDistances and WithPositions
+type GeoResult struct {
+	Member string
+
+	//Distance is how far the member is from the center of the search, in the unit of the search's radius or box
+	Distance float64
+
+	Position GeoPosition
+}
+
+//Geo is a geospatial index - a sorted set of members with the position of each member encoded in its score.
+//See http://redis.io/commands#geo for more information on geospatial indexes
+type Geo struct {
+	SortableKey
+}
+
+func newGeo(client SafeExecutor, key string) Geo {
+	return Geo{
+		newSortableKey(client, key),
+	}
+}
+
+func parseGeoPosition(r *response) *GeoPosition {
+	values := r.values()
+	if len(values) < 2 {
+		return nil
+	}
+	longitude, err := atof(values[0])
+	if err != nil {
+		return nil
+	}
+	latitude, err := atof(values[1])
+	if err != nil {
+		return nil
+	}
+	return &GeoPosition{longitude, latitude}
+}
+
+//IsValid returns whether the underlying redis object can use the commands in this object
+func (this Geo) IsValid() <-chan bool {
+	c := make(chan bool, 1)
+	go func() {
+		defer close(c)
+		c <- (<-this.Type() == "zset")
+	}()
+	return c
+}
+
+//GEOADD command -
+//Add adds members to the index, or moves them if they're already part of it;
+//returns the number of members that were added
+func (this Geo) Add(locations ...GeoLocation) <-chan int {
+	return this.AddWithOptions(GeoAddOptions{}, locations...)
+}
+
+//GEOADD command -
+//AddWithOptions adds or moves members according to the options;
+//returns the number of members that were added (and moved, with CountChanged)
+func (this Geo) AddWithOptions(options GeoAddOptions, locations ...GeoLocation) <-chan int {
+	args := options.args()
+	for _, location := range locations {
+		args = append(args, ftoa(location.Longitude), ftoa(location.Latitude), location.Member)
+	}
+	return IntCommand(this, this.args("geoadd", args...)...)
+}
+
+//ZREM command -
+//Remove removes members from the index; returns the number of members that were removed
+func (this Geo) Remove(members ...string) <-chan int {
+	return IntCommand(this, this.args("zrem", members...)...)
+}
+
+//ZCARD command -
+//Size returns the number of members of the index
+func (this Geo) Size() <-chan int {
+	return IntCommand(this, this.args("zcard")...)
+}
+
+//GEOPOS command -
+//Positions returns the position of each member, in the same order; members that aren't part of the index have a nil position
+func (this Geo) Positions(members ...string) <-chan []*GeoPosition {
+	out := make(chan []*GeoPosition, 1)
+	in := responseChannel(this, this.args("geopos", members...)...)
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok {
+			return
+		}
+		positions := make([]*GeoPosition, len(r.subresponses))
+		for i, sub := range r.subresponses {
+			positions[i] = parseGeoPosition(sub)
+		}
+		out <- positions
+	}()
+	return out
+}
+
+//GEOPOS command -
+//PositionOf returns the position of a member; nothing is returned if it isn't part of the index
+func (this Geo) PositionOf(member string) <-chan GeoPosition {
+	out := make(chan GeoPosition, 1)
+	in := this.Positions(member)
+	go func() {
+		defer close(out)
+		if positions, ok := <-in; ok && len(positions) == 1 && positions[0] != nil {
+			out <- *positions[0]
+		}
+	}()
+	return out
+}
+
+//GEODIST command -
+//Distance returns the distance between two members; nothing is returned if either of them isn't part of the index
+func (this Geo) Distance(from, to string, unit GeoUnit) <-chan float64 {
+	return FloatCommand(this, this.args("geodist", from, to, string(unit))...)
+}
+
+//GEOHASH command -
+//Hashes returns the geohash of each member, in the same order; members that aren't part of the index have an empty hash
+func (this Geo) Hashes(members ...string) <-chan []string {
+	return SliceCommand(this, this.args("geohash", members...)...)
+}
+
+//Near creates a GeoSearch for the members around a position.
+//Use either WithinRadius or WithinBox to say how far around it to search
+func (this Geo) Near(position GeoPosition) *GeoSearch {
+	return &GeoSearch{
+		key:  this.Key,
+		from: []string{"FROMLONLAT", ftoa(position.Longitude), ftoa(position.Latitude)},
+	}
+}
+
+//NearMember creates a GeoSearch for the members around another member
+func (this Geo) NearMember(member string) *GeoSearch {
+	return &GeoSearch{
+		key:  this.Key,
+		from: []string{"FROMMEMBER", member},
+	}
+}
+
+//Use allows you to use this key on a different executor
+func (this Geo) Use(e SafeExecutor) Geo {
+	this.client = e
+	return this
+}
+
+//GeoSearch keeps track of the area and options of a search of a Geo index
+type GeoSearch struct {
+	key       Key
+	from      []string
+	by        []string
+	order     string
+	count     int
+	any       bool
+	distances bool
+	positions bool
+}
+
+//WithinRadius searches a circle around the center
+func (this *GeoSearch) WithinRadius(radius float64, unit GeoUnit) *GeoSearch {
+	this.by = []string{"BYRADIUS", ftoa(radius), string(unit)}
+	return this
+}
+
+//WithinBox searches a rectangle around the center
+func (this *GeoSearch) WithinBox(width, height float64, unit GeoUnit) *GeoSearch {
+	this.by = []string{"BYBOX", ftoa(width), ftoa(height), string(unit)}
+	return this
+}
+
+//Nearest returns the results nearest first (otherwise they aren't in any particular order)
+func (this *GeoSearch) Nearest() *GeoSearch {
+	this.order = "ASC"
+	return this
+}
+
+//Farthest returns the results farthest first
+func (this *GeoSearch) Farthest() *GeoSearch {
+	this.order = "DESC"
+	return this
+}
+
+//Limit only returns the first "count" results.
+//If "any" is set, redis stops searching as soon as it has found enough members, so they might not be the nearest ones
+func (this *GeoSearch) Limit(count int, any bool) *GeoSearch {
+	this.count = count
+	this.any = any
+	return this
+}
+
+//WithDistances fills in the Distance of each GeoResult
+func (this *GeoSearch) WithDistances() *GeoSearch {
+	this.distances = true
+	return this
+}
+
+//WithPositions fills in the Position of each GeoResult
+func (this *GeoSearch) WithPositions() *GeoSearch {
+	this.positions = true
+	return this
+}
+
+func (this *GeoSearch) args() []string {
+	result := append(append([]string{}, this.from...), this.by...)
+	if this.order != "" {
+		result = append(result, this.order)
+	}
+	if this.count > 0 {
+		result = append(result, "COUNT", itoa(this.count))
+		if this.any {
+			result = append(result, "ANY")
+		}
+	}
+	return result
+}
+
+//GEOSEARCH command -
+//Get returns the members that were found
+func (this *GeoSearch) Get() <-chan []string {
+	return SliceCommand(this.key, this.key.args("geosearch", this.args()...)...)
+}
+
+//GEOSEARCH command -
+//GetResults returns the members that were found, along with their distances and positions (if they were asked for)
+func (this *GeoSearch) GetResults() <-chan []GeoResult {
+	args := this.args()
+	if this.distances {
+		args = append(args, "WITHDIST")
+	}
+	if this.positions {
+		args = append(args, "WITHCOORD")
+	}
+
+	out := make(chan []GeoResult, 1)
+	in := responseChannel(this.key, this.key.args("geosearch", args...)...)
+	distances, positions := this.distances, this.positions
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok {
+			return
+		}
+		results := make([]GeoResult, 0, len(r.subresponses))
+		for _, sub := range r.subresponses {
+			if sub == nil {
+				continue
+			}
+			//a result is just the member, unless anything else was asked for - then it's the member, distance, and position, in that order
+			if sub.subresponses == nil {
+				results = append(results, GeoResult{Member: sub.val})
+				continue
+			}
+			fields := sub.subresponses
+			result := GeoResult{Member: fields[0].value()}
+			i := 1
+			if distances && i < len(fields) {
+				result.Distance, _ = atof(fields[i].value())
+				i++
+			}
+			if positions && i < len(fields) {
+				if position := parseGeoPosition(fields[i]); position != nil {
+					result.Position = *position
+				}
+			}
+			results = append(results, result)
+		}
+		out <- results
+	}()
+	return out
+}
+
+//GEOSEARCHSTORE command -
+//StoreIn stores the members that were found (and their positions) in another Geo index, replacing anything it held;
+//returns the number of members stored
+func (this *GeoSearch) StoreIn(destination Geo) <-chan int {
+	return IntCommand(this.key, append([]string{"GEOSEARCHSTORE", destination.key, this.key.key}, this.args()...)...)
+}
+
+//GEOSEARCHSTORE command -
+//StoreDistancesIn stores the members that were found in a sorted set, scored by their distance from the center of the search;
+//returns the number of members stored
+func (this *GeoSearch) StoreDistancesIn(destination SortedSet) <-chan int {
+	return IntCommand(this.key, append(append([]string{"GEOSEARCHSTORE", destination.key, this.key.key}, this.args()...), "STOREDIST")...)
+}
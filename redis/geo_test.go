@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeo(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	g := r.Prefix("Test_").Geo("Geo")
+	<-g.Delete()
+
+	palermo := GeoLocation{"Palermo", GeoPosition{13.361389, 38.115556}}
+	catania := GeoLocation{"Catania", GeoPosition{15.087269, 37.502669}}
+	rome := GeoLocation{"Rome", GeoPosition{12.496366, 41.902782}}
+
+	if added := <-g.Add(palermo, catania); added != 2 {
+		t.Error("Should have added 2 members, not", added)
+	}
+	if added := <-g.AddWithOptions(GeoAddOptions{OnlyNew: true}, palermo, rome); added != 1 {
+		t.Error("Should only have added Rome, not", added)
+	}
+	if size := <-g.Size(); size != 3 {
+		t.Error("Should have 3 members, not", size)
+	}
+
+	if distance := <-g.Distance("Palermo", "Catania", Kilometers); math.Abs(distance-166.27) > 0.1 {
+		t.Error("Palermo should be about 166km from Catania, not", distance)
+	}
+	if _, ok := <-g.Distance("Palermo", "Nowhere", Meters); ok {
+		t.Error("Shouldn't have a distance to a missing member")
+	}
+	positions := <-g.Positions("Catania", "Nowhere")
+	if len(positions) != 2 || positions[0] == nil || math.Abs(positions[0].Latitude-catania.Latitude) > 0.001 || positions[1] != nil {
+		t.Error("Got the wrong positions:", positions)
+	}
+	if position := <-g.PositionOf("Palermo"); math.Abs(position.Longitude-palermo.Longitude) > 0.001 {
+		t.Error("Got the wrong position for Palermo:", position)
+	}
+	if hashes := <-g.Hashes("Palermo"); len(hashes) != 1 || hashes[0] != "sqc8b49rny0" {
+		t.Error("Got the wrong geohash for Palermo:", hashes)
+	}
+
+	near := <-g.Near(GeoPosition{15, 37}).WithinRadius(200, Kilometers).Nearest().Get()
+	if len(near) != 2 || near[0] != "Catania" || near[1] != "Palermo" {
+		t.Error("Should have found Catania and then Palermo, not", near)
+	}
+	results := <-g.NearMember("Palermo").WithinBox(1000, 1000, Kilometers).Farthest().Limit(2, false).WithDistances().WithPositions().GetResults()
+	if len(results) != 2 || results[0].Member != "Rome" || results[0].Distance < 400 || math.Abs(results[0].Position.Latitude-rome.Latitude) > 0.001 {
+		t.Error("Should have found Rome first, not", results)
+	}
+
+	stored := r.Geo("Test_GeoStored")
+	if count := <-g.NearMember("Rome").WithinRadius(100, Kilometers).StoreIn(stored); count != 1 {
+		t.Error("Should have stored Rome, not", count)
+	}
+	distances := r.SortedSet("Test_GeoDistances")
+	if count := <-g.Near(GeoPosition{15, 37}).WithinRadius(200, Kilometers).StoreDistancesIn(distances); count != 2 {
+		t.Error("Should have stored 2 distances, not", count)
+	}
+	if removed := <-g.Remove("Rome", "Nowhere"); removed != 1 {
+		t.Error("Should have removed Rome, not", removed)
+	}
+}
@@ -1,6 +1,8 @@
 package redis
 
 import (
+	"io"
+	"iter"
 	"strings"
 )
 
@@ -53,6 +55,21 @@ func (this Hash) Get() <-chan map[string]string {
 	return MapCommand(this, this.args("hgetall")...)
 }
 
+//HSCAN command -
+//Scan sends back every field in the hash that fits the options (along with its value) through a channel, a few at a time.
+//Warning - this keeps using network I/O until every field has been sent back, or until you signal that you're done
+func (this Hash) Scan(options ScanOptions) (entries <-chan HashEntry, finishSignaler io.Closer) {
+	return seq2Channel(this.All(options), func(field, value string) HashEntry {
+		return HashEntry{field, value}
+	})
+}
+
+//HSCAN command -
+//All is like Scan, but gives back an iterator of fields and values to be used with range instead of a channel
+func (this Hash) All(options ScanOptions) iter.Seq2[string, string] {
+	return scanPairSeq(this, this.args("hscan"), options)
+}
+
 //HashField implements basic functions that apply to Hash Fields
 type HashField struct {
 	parent Hash
@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"time"
+)
+
+//HyperLogLog is an object that estimates how many unique items have been added to it, using very little memory
+//(a standard error of 0.81%, in no more than 12kB).
+//See http://redis.io/commands#hyperloglog for more information on HyperLogLogs
+type HyperLogLog struct {
+	Key
+}
+
+func newHyperLogLog(client SafeExecutor, key string) HyperLogLog {
+	return HyperLogLog{
+		newKey(client, key),
+	}
+}
+
+func hyperLogLogKeys(hlls []HyperLogLog) []string {
+	keys := make([]string, len(hlls))
+	for i, hll := range hlls {
+		keys[i] = hll.key
+	}
+	return keys
+}
+
+//IsValid returns whether the underlying redis object can use the commands in this object
+func (this HyperLogLog) IsValid() <-chan bool {
+	c := make(chan bool, 1)
+	go func() {
+		defer close(c)
+		c <- (<-this.Type() == "string")
+	}()
+	return c
+}
+
+//PFADD command -
+//Add adds items to the HyperLogLog; returns whether or not the estimated count changed
+func (this HyperLogLog) Add(items ...string) <-chan bool {
+	return BoolCommand(this, this.args("pfadd", items...)...)
+}
+
+//PFCOUNT command -
+//Count returns the estimated number of unique items that have been added
+func (this HyperLogLog) Count() <-chan int {
+	return IntCommand(this, this.args("pfcount")...)
+}
+
+//PFCOUNT command -
+//CountWith returns the estimated number of unique items that have been added to this HyperLogLog or any of the others
+func (this HyperLogLog) CountWith(others ...HyperLogLog) <-chan int {
+	return IntCommand(this, this.args("pfcount", hyperLogLogKeys(others)...)...)
+}
+
+//PFMERGE command -
+//StoreUnionOf adds the items of the other HyperLogLogs to this one, so that it counts every unique item in any of them
+func (this HyperLogLog) StoreUnionOf(others ...HyperLogLog) <-chan nothing {
+	return NilCommand(this, this.args("pfmerge", hyperLogLogKeys(others)...)...)
+}
+
+//Buckets divides this HyperLogLog up by time, counting the unique items added within each period of the specified size (e.g. 24 hours for daily counts).
+//Each bucket is a separate HyperLogLog, named after this one and the time that its period begins (in UTC).
+//The size must be positive - there are no buckets between any two times otherwise.
+//This is a lightweight function - does *not* involve network I/O
+func (this HyperLogLog) Buckets(size time.Duration) HyperLogLogBuckets {
+	return HyperLogLogBuckets{
+		root: this,
+		size: size,
+	}
+}
+
+//Use allows you to use this key on a different executor
+func (this HyperLogLog) Use(e SafeExecutor) HyperLogLog {
+	this.client = e
+	return this
+}
+
+//HyperLogLogBuckets counts unique items per period of time, such as daily unique visitors
+type HyperLogLogBuckets struct {
+	root   HyperLogLog
+	size   time.Duration
+	expire time.Duration
+}
+
+//ExpireAfter makes each bucket expire once this long has passed since the end of its period.
+//By default the buckets never expire
+func (this HyperLogLogBuckets) ExpireAfter(duration time.Duration) HyperLogLogBuckets {
+	this.expire = duration
+	return this
+}
+
+func (this HyperLogLogBuckets) start(at time.Time) time.Time {
+	return at.UTC().Truncate(this.size)
+}
+
+//layout only includes as much of the time as is needed to tell the buckets apart
+func (this HyperLogLogBuckets) layout() string {
+	switch {
+	case this.size%(24*time.Hour) == 0:
+		return "2006-01-02"
+	case this.size%time.Hour == 0:
+		return "2006-01-02T15"
+	case this.size%time.Minute == 0:
+		return "2006-01-02T15:04"
+	}
+	return "2006-01-02T15:04:05"
+}
+
+//Bucket returns the HyperLogLog that counts the items added during the period that includes the specified time
+func (this HyperLogLogBuckets) Bucket(at time.Time) HyperLogLog {
+	hll := this.root
+	hll.key += ":" + this.start(at).Format(this.layout())
+	return hll
+}
+
+//BucketsBetween returns the HyperLogLogs of every period from the one including "from" to the one including "to"
+func (this HyperLogLogBuckets) BucketsBetween(from, to time.Time) []HyperLogLog {
+	result := []HyperLogLog{}
+	if this.size <= 0 {
+		return result
+	}
+	for at := this.start(from); !at.After(to); at = at.Add(this.size) {
+		result = append(result, this.Bucket(at))
+	}
+	return result
+}
+
+//PFADD command -
+//Add adds items to the bucket of the period that includes the specified time; returns whether or not its estimated count changed
+func (this HyperLogLogBuckets) Add(at time.Time, items ...string) <-chan bool {
+	bucket := this.Bucket(at)
+	result := bucket.Add(items...)
+	if this.expire > 0 {
+		bucket.ExpireAt(this.start(at).Add(this.size + this.expire))
+	}
+	return result
+}
+
+//PFCOUNT command -
+//Count returns the estimated number of unique items added during the period that includes the specified time
+func (this HyperLogLogBuckets) Count(at time.Time) <-chan int {
+	return this.Bucket(at).Count()
+}
+
+//PFCOUNT command -
+//CountBetween returns the estimated number of unique items added during any of the periods from "from" to "to"
+//(each item is only counted once, even if it was added in several periods)
+func (this HyperLogLogBuckets) CountBetween(from, to time.Time) <-chan int {
+	buckets := this.BucketsBetween(from, to)
+	if len(buckets) == 0 {
+		out := make(chan int, 1)
+		out <- 0
+		close(out)
+		return out
+	}
+	return buckets[0].CountWith(buckets[1:]...)
+}
+
+//PFMERGE command -
+//StoreBetween adds the items of every period from "from" to "to" to the destination HyperLogLog
+func (this HyperLogLogBuckets) StoreBetween(destination HyperLogLog, from, to time.Time) <-chan nothing {
+	return destination.StoreUnionOf(this.BucketsBetween(from, to)...)
+}
+
+//Use allows you to use these buckets on a different executor
+func (this HyperLogLogBuckets) Use(e SafeExecutor) HyperLogLogBuckets {
+	this.root = this.root.Use(e)
+	return this
+}
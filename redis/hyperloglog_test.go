@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_HLL:")
+	a, b, union := p.HyperLogLog("A"), p.HyperLogLog("B"), p.HyperLogLog("Union")
+	<-a.Delete()
+	<-b.Delete()
+	<-union.Delete()
+
+	if changed := <-a.Add("x", "y", "z"); !changed {
+		t.Error("Adding new items should change the count")
+	}
+	if changed := <-a.Add("x"); changed {
+		t.Error("Adding an item again shouldn't change the count")
+	}
+	<-b.Add("z", "w")
+	if count := <-a.Count(); count != 3 {
+		t.Error("A should count 3 items, not", count)
+	}
+	if count := <-a.CountWith(b); count != 4 {
+		t.Error("A and B should count 4 items between them, not", count)
+	}
+	<-union.StoreUnionOf(a, b)
+	if count := <-union.Count(); count != 4 {
+		t.Error("The union should count 4 items, not", count)
+	}
+	if valid := <-union.IsValid(); !valid {
+		t.Error("The union should be valid")
+	}
+}
+
+func TestHyperLogLogBuckets(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	daily := r.HyperLogLog("Test_HLL_Visitors").Buckets(24 * time.Hour).ExpireAfter(time.Hour)
+	midnight := time.Now().UTC().Truncate(24 * time.Hour)
+	today := midnight.Add(9*time.Hour + 30*time.Minute)
+	tomorrow := today.Add(24 * time.Hour)
+	dayAfter := tomorrow.Add(24 * time.Hour)
+	for _, day := range []time.Time{today, tomorrow, dayAfter} {
+		<-daily.Bucket(day).Delete()
+	}
+
+	if name := daily.Bucket(today).keyName(); name != "Test_HLL_Visitors:"+midnight.Format("2006-01-02") {
+		t.Error("The bucket is named", name)
+	}
+	<-daily.Add(today, "alice", "bob")
+	<-daily.Add(tomorrow.Add(10*time.Hour), "bob", "carol")
+	<-daily.Add(dayAfter, "dave")
+
+	if count := <-daily.Count(today.Add(12 * time.Hour)); count != 2 {
+		t.Error("Should have counted 2 visitors today, not", count)
+	}
+	if count := <-daily.CountBetween(today, tomorrow); count != 3 {
+		t.Error("Should have counted 3 visitors today and tomorrow, not", count)
+	}
+	if buckets := daily.BucketsBetween(today, dayAfter); len(buckets) != 3 {
+		t.Error("Should be 3 buckets from today to the day after tomorrow, not", len(buckets))
+	}
+	if buckets := r.HyperLogLog("Test_HLL_Visitors").Buckets(0).BucketsBetween(today, dayAfter); len(buckets) != 0 {
+		t.Error("Should be no buckets without a size, not", len(buckets))
+	}
+
+	total := r.HyperLogLog("Test_HLL_Visitors_Total")
+	<-total.Delete()
+	<-daily.StoreBetween(total, today, dayAfter)
+	if count := <-total.Count(); count != 4 {
+		t.Error("Should have counted 4 visitors from today to the day after tomorrow, not", count)
+	}
+	if expires := <-daily.Bucket(today).ExpiresAt(); !expires.Equal(midnight.Add(25 * time.Hour)) {
+		t.Error("Today's bucket should expire an hour after the end of the day, not", expires)
+	}
+}
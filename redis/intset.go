@@ -1,5 +1,10 @@
 package redis
 
+import (
+	"io"
+	"iter"
+)
+
 //TODO: refactor to use Set code
 
 //IntSet implements the Redis Set primitive assuming all inputs are integers (which is useful for indexes)
@@ -136,6 +141,19 @@ func (this IntSet) MoveMemberTo(newSet IntSet, item int) <-chan bool {
 	return BoolCommand(this, this.args("smove", newSet.key, itoa(item))...)
 }
 
+//SSCAN command -
+//Scan sends back every integer in the set that fits the options through a channel, a few at a time.
+//Warning - this keeps using network I/O until every integer has been sent back, or until you signal that you're done
+func (this IntSet) Scan(options ScanOptions) (members <-chan int, finishSignaler io.Closer) {
+	return seqChannel(this.All(options))
+}
+
+//SSCAN command -
+//All is like Scan, but gives back an iterator to be used with range instead of a channel
+func (this IntSet) All(options ScanOptions) iter.Seq[int] {
+	return intSeq(scanSeq(this, this.args("sscan"), options))
+}
+
 //Use allows you to use this key on a different executor
 func (this IntSet) Use(e SafeExecutor) IntSet {
 	this.client = e
@@ -12,6 +12,38 @@ type Key struct {
 	client SafeExecutor
 }
 
+//ExpireCondition decides whether or not an expiration should be set, based on the one the key already has (requires redis 7)
+type ExpireCondition string
+
+const (
+	//ExpireAlways sets the expiration no matter what
+	ExpireAlways ExpireCondition = ""
+
+	//ExpireIfPersistent only sets the expiration if the key isn't already set to expire (NX)
+	ExpireIfPersistent ExpireCondition = "NX"
+
+	//ExpireIfExpiring only sets the expiration if the key is already set to expire (XX)
+	ExpireIfExpiring ExpireCondition = "XX"
+
+	//ExpireIfLater only sets the expiration if it is later than the current one (GT); keys that aren't set to expire count as expiring never
+	ExpireIfLater ExpireCondition = "GT"
+
+	//ExpireIfSooner only sets the expiration if it is sooner than the current one (LT); keys that aren't set to expire count as expiring never
+	ExpireIfSooner ExpireCondition = "LT"
+)
+
+func (this ExpireCondition) args(arguments ...string) []string {
+	if this == ExpireAlways {
+		return arguments
+	}
+	return append(arguments, string(this))
+}
+
+//Keyed is anything that is based on a single redis key (Key, String, List, Hash, etc.)
+type Keyed interface {
+	keyName() string
+}
+
 func newKey(client SafeExecutor, key string) Key {
 	return Key{
 		key:    key,
@@ -19,6 +51,10 @@ func newKey(client SafeExecutor, key string) Key {
 	}
 }
 
+func (this Key) keyName() string {
+	return this.key
+}
+
 func (this Key) args(command string, arguments ...string) []string {
 	return append([]string{strings.ToUpper(command), this.key}, arguments...)
 }
@@ -59,12 +95,19 @@ func (this Key) MoveToIfEmpty(other Key) <-chan bool {
 //Currently, if the duration is less than an hour, it will set the duration to the nearest millisecond;
 //if the duration is greater than or equal to an hour, it will set the duration to the nearest second instead
 func (this Key) ExpireIn(duration time.Duration) <-chan bool {
+	return this.ExpireInIf(duration, ExpireAlways)
+}
+
+//PEXPIRE or EXPIRE command -
+//ExpireInIf sets the key to expire after a specified duration, but only if the condition is met.
+//The duration is rounded in the same way as ExpireIn
+func (this Key) ExpireInIf(duration time.Duration, condition ExpireCondition) <-chan bool {
 	//if the time to expire is in a time range larger than an hour, the number of milliseconds probably is not particularly important, so we can use a regular expire
 	if duration >= time.Hour {
-		return BoolCommand(this, this.args("expire", itoa(int(duration/time.Second)))...)
+		return BoolCommand(this, this.args("expire", condition.args(itoa(int(duration/time.Second)))...)...)
 	}
 	//otherwise use pexpire to get down to the nearest millisecond
-	return BoolCommand(this, this.args("pexpire", itoa(int(duration/time.Millisecond)))...)
+	return BoolCommand(this, this.args("pexpire", condition.args(itoa(int(duration/time.Millisecond)))...)...)
 }
 
 //EXPIREAT command - 
@@ -73,6 +116,41 @@ func (this Key) ExpireAt(timestamp time.Time) <-chan bool {
 	return BoolCommand(this, this.args("expireat", itoa(int(timestamp.Unix())))...)
 }
 
+//PEXPIREAT command -
+//ExpireAtIf sets the key to expire at a specific time (to the nearest millisecond), but only if the condition is met
+func (this Key) ExpireAtIf(timestamp time.Time, condition ExpireCondition) <-chan bool {
+	return BoolCommand(this, this.args("pexpireat", condition.args(itoa(int(timestamp.UnixMilli())))...)...)
+}
+
+//PERSIST command -
+//Persist removes the expiration from the key, so that it will stay around until it gets deleted;
+//returns whether or not the key had been set to expire
+func (this Key) Persist() <-chan bool {
+	return BoolCommand(this, this.args("persist")...)
+}
+
+//PEXPIRETIME command -
+//ExpiresAt returns the time at which the key is set to expire (requires redis 7).
+//If the key doesn't exist, or isn't set to expire, nothing is returned
+func (this Key) ExpiresAt() <-chan time.Time {
+	out := make(chan time.Time, 1)
+	in := IntCommand(this, this.args("pexpiretime")...)
+	go func() {
+		defer close(out)
+		if ms, ok := <-in; ok && ms >= 0 {
+			out <- time.UnixMilli(int64(ms))
+		}
+	}()
+	return out
+}
+
+//PTTL command -
+//TimeToLive returns how much longer the key has until it expires.
+//If the key doesn't exist, or isn't set to expire, nothing is returned
+func (this Key) TimeToLive() <-chan time.Duration {
+	return durationChannel(this.MillisecondsToLive(), time.Millisecond)
+}
+
 //TTL command - 
 //SecondsToLive returns to number of seconds until this key is set to expire
 func (this Key) SecondsToLive() <-chan int {
@@ -85,6 +163,97 @@ func (this Key) MillisecondsToLive() <-chan int {
 	return IntCommand(this, this.args("pttl")...)
 }
 
+//TOUCH command -
+//Touch updates the last time the key was accessed (as if it had been read);
+//returns whether or not the key exists
+func (this Key) Touch() <-chan bool {
+	return BoolCommand(this, this.args("touch")...)
+}
+
+//UNLINK command -
+//Unlink removes a key from Redis like Delete does, but frees up its memory in the background
+//(which is much faster for large keys)
+func (this Key) Unlink() <-chan bool {
+	return BoolCommand(this, this.args("unlink")...)
+}
+
+//COPY command -
+//CopyTo copies this key to a different one (requires redis 6.2).
+//If the other key already exists, it only gets overwritten if "replace" is true;
+//returns whether or not the copy happened
+func (this Key) CopyTo(other Key, replace bool) <-chan bool {
+	args := []string{other.key}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	return BoolCommand(this, this.args("copy", args...)...)
+}
+
+//COPY command -
+//CopyToDatabase copies this key to a different one within another database (requires redis 6.2).
+//If the other key already exists, it only gets overwritten if "replace" is true;
+//returns whether or not the copy happened
+func (this Key) CopyToDatabase(db int, other Key, replace bool) <-chan bool {
+	args := []string{other.key, "DB", itoa(db)}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	return BoolCommand(this, this.args("copy", args...)...)
+}
+
+//MOVE command -
+//MoveToDatabase moves this key (under the same name) into another database.
+//Nothing happens if the key already exists in the other database;
+//returns whether or not the move happened
+func (this Key) MoveToDatabase(db int) <-chan bool {
+	return BoolCommand(this, this.args("move", itoa(db))...)
+}
+
+//DUMP command -
+//Dump returns the value of the key serialized into redis's own format, which can be used with Restore.
+//If the key doesn't exist, nothing is returned
+func (this Key) Dump() <-chan string {
+	return StringCommand(this, this.args("dump")...)
+}
+
+//RESTORE command -
+//Restore creates the key using a value that was serialized by Dump.
+//If "ttl" is 0, the key won't be set to expire.
+//If the key already exists, it only gets overwritten if "replace" is true
+func (this Key) Restore(payload string, ttl time.Duration, replace bool) <-chan nothing {
+	args := []string{itoa(int(ttl / time.Millisecond)), payload}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	return NilCommand(this, this.args("restore", args...)...)
+}
+
+//OBJECT ENCODING command -
+//Encoding returns how redis is storing the key internally (e.g. "listpack", "hashtable", "int", "embstr")
+func (this Key) Encoding() <-chan string {
+	return StringCommand(this, "OBJECT", "ENCODING", this.key)
+}
+
+//OBJECT IDLETIME command -
+//IdleTime returns how long it has been since the key was last accessed (to the nearest second).
+//Only available when redis is not using an LFU eviction policy
+func (this Key) IdleTime() <-chan time.Duration {
+	return durationChannel(IntCommand(this, "OBJECT", "IDLETIME", this.key), time.Second)
+}
+
+//OBJECT FREQ command -
+//AccessFrequency returns the logarithmic counter redis uses to keep track of how often the key is accessed.
+//Only available when redis is using an LFU eviction policy
+func (this Key) AccessFrequency() <-chan int {
+	return IntCommand(this, "OBJECT", "FREQ", this.key)
+}
+
+//OBJECT REFCOUNT command -
+//ReferenceCount returns the number of references redis has to the value of the key
+func (this Key) ReferenceCount() <-chan int {
+	return IntCommand(this, "OBJECT", "REFCOUNT", this.key)
+}
+
 //Execute allows the Key to be an Executor, which makes things quicker to code
 func (this Key) Execute(command command) {
 	this.client.Execute(command)
@@ -98,3 +98,132 @@ func TestKeys(t *testing.T) {
 		t.Error("Should have expired, instead has ", res)
 	}
 }
+
+func TestKeyLifecycle(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	str := r.String("Test_Key_Lifecycle")
+	other := r.String("Other_Test_Key_Lifecycle")
+	<-str.Delete()
+	<-other.Delete()
+
+	<-str.Set("A")
+	if _, ok := <-str.TimeToLive(); ok {
+		t.Error("Key should not have a TTL yet")
+	}
+	if _, ok := <-str.ExpiresAt(); ok {
+		t.Error("Key should not have an expiration time yet")
+	}
+
+	if <-str.ExpireInIf(time.Hour, ExpireIfExpiring) {
+		t.Error("Should not set a TTL on a key that isn't expiring")
+	}
+	if !<-str.ExpireInIf(time.Hour, ExpireIfPersistent) {
+		t.Error("Should set a TTL on a key that isn't expiring")
+	}
+	if <-str.ExpireInIf(time.Minute, ExpireIfLater) {
+		t.Error("Should not shorten the TTL")
+	}
+	if !<-str.ExpireInIf(time.Minute, ExpireIfSooner) {
+		t.Error("Should shorten the TTL")
+	}
+	if res := <-str.TimeToLive(); res <= 59*time.Second || res > time.Minute {
+		t.Error("Should have about a minute to live, not", res)
+	}
+
+	expiration := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	if !<-str.ExpireAtIf(expiration, ExpireAlways) {
+		t.Error("Should be able to set the expiration time")
+	}
+	if res := <-str.ExpiresAt(); !res.Equal(expiration) {
+		t.Error("Should expire at", expiration, "not", res)
+	}
+
+	if !<-str.Persist() {
+		t.Error("Key should have been expiring")
+	}
+	if _, ok := <-str.TimeToLive(); ok {
+		t.Error("Key should not have a TTL anymore")
+	}
+
+	if !<-str.Touch() {
+		t.Error("Should be able to touch an existing key")
+	}
+	if res := <-str.Encoding(); res != "embstr" {
+		t.Error("Short string should be encoded as embstr, not", res)
+	}
+	if res, ok := <-str.IdleTime(); !ok || res > time.Second {
+		t.Error("Should have just been accessed, not", res, "ago")
+	}
+	if res := <-str.ReferenceCount(); res < 1 {
+		t.Error("Should have at least one reference, not", res)
+	}
+
+	if !<-str.CopyTo(other.Key, false) {
+		t.Error("Should be able to copy to an empty key")
+	}
+	<-str.Set("B")
+	if <-str.CopyTo(other.Key, false) {
+		t.Error("Should not copy over an existing key")
+	}
+	if res := <-other.Get(); res != "A" {
+		t.Error("Should still be A, not", res)
+	}
+	if !<-str.CopyTo(other.Key, true) {
+		t.Error("Should be able to replace an existing key")
+	}
+	if res := <-other.Get(); res != "B" {
+		t.Error("Should now be B, not", res)
+	}
+
+	dump, ok := <-str.Dump()
+	if !ok {
+		t.Fatal("Should be able to dump the key")
+	}
+	<-str.Unlink()
+	if <-str.Exists() {
+		t.Error("Key should have been unlinked")
+	}
+	<-str.Restore(dump, time.Hour, false)
+	if res := <-str.Get(); res != "B" {
+		t.Error("Should have restored B, not", res)
+	}
+	if _, ok := <-str.TimeToLive(); !ok {
+		t.Error("Restored key should have a TTL")
+	}
+
+	config := DefaultConfiguration()
+	config.DBid = 1
+	config.ConnectionCount = 1
+	r1, err := New(config)
+	if err != nil {
+		t.Fatal("Can't load redis - " + err.Error())
+	}
+	defer r1.Close()
+	<-r1.Key("Test_Key_Lifecycle").Delete()
+	<-r1.Key("Other_Test_Key_Lifecycle").Delete()
+
+	if !<-str.CopyToDatabase(1, other.Key, false) {
+		t.Error("Should be able to copy to another database")
+	}
+	if res := <-r1.String("Other_Test_Key_Lifecycle").Get(); res != "B" {
+		t.Error("Copy in the other database should be B, not", res)
+	}
+	if <-other.MoveToDatabase(1) {
+		t.Error("Should not be able to move onto a key that exists in the other database")
+	}
+	if !<-str.MoveToDatabase(1) {
+		t.Error("Should be able to move to another database")
+	}
+	if <-str.Exists() {
+		t.Error("Key should have moved out of this database")
+	}
+	if res := <-r1.String("Test_Key_Lifecycle").Get(); res != "B" {
+		t.Error("Moved key should be B, not", res)
+	}
+
+	<-r1.Key("Test_Key_Lifecycle").Delete()
+	<-r1.Key("Other_Test_Key_Lifecycle").Delete()
+	<-other.Delete()
+}
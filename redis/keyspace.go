@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+//The names of the more common keyspace events.
+//See http://redis.io/topics/notifications for every event that each command generates
+const (
+	EventSet     = "set"
+	EventDelete  = "del"
+	EventExpire  = "expire"
+	EventExpired = "expired"
+	EventEvicted = "evicted"
+	EventRenamed = "rename_to"
+	EventLPush   = "lpush"
+	EventRPush   = "rpush"
+	EventSAdd    = "sadd"
+	EventZAdd    = "zadd"
+	EventHSet    = "hset"
+	EventIncrBy  = "incrby"
+	EventNew     = "new"
+)
+
+//allEventClasses are the classes of events that "A" stands for in notify-keyspace-events
+const allEventClasses = "g$lshzxetd"
+
+//eventClasses are the notify-keyspace-events classes that the events above belong to
+var eventClasses = map[string]rune{
+	EventSet:     '$',
+	EventDelete:  'g',
+	EventExpire:  'g',
+	EventExpired: 'x',
+	EventEvicted: 'e',
+	EventRenamed: 'g',
+	EventLPush:   'l',
+	EventRPush:   'l',
+	EventSAdd:    's',
+	EventZAdd:    'z',
+	EventHSet:    'h',
+	EventIncrBy:  '$',
+	EventNew:     'n',
+}
+
+//KeyEvent is a single notification that a command has changed (or a key has expired or been evicted)
+type KeyEvent struct {
+	//Key is the name of the key, relative to the namespace that the KeyEvents were created within
+	//(events of keys outside of the namespace aren't sent back down)
+	Key string
+
+	//Event is the kind of change, such as EventSet or EventExpired
+	Event string
+}
+
+//KeyEvents defines a subscription to the keyspace notifications of a single key, or of every key within a namespace (through __keyspace@N__ channels),
+//or to the keyevent notifications of specific events, such as every key that expired (through __keyevent@N__ channels).
+//See http://redis.io/topics/notifications for more information on keyspace notifications
+type KeyEvents struct {
+	client   *Client
+	root     string
+	channel  string
+	pattern  bool
+	keyevent bool
+	events   map[string]bool
+}
+
+func newKeyEvents(client *Client, root, key string) KeyEvents {
+	return KeyEvents{
+		client:  client,
+		root:    root,
+		channel: keyspaceChannel(client) + root + key,
+	}
+}
+
+func newPrefixKeyEvents(client *Client, root string) KeyEvents {
+	return KeyEvents{
+		client:  client,
+		root:    root,
+		channel: keyspaceChannel(client) + escapeGlob(root) + "*",
+		pattern: true,
+	}
+}
+
+//newEventKeyEvents subscribes to the keyevent channels of the events (or of every event, if there aren't any)
+func newEventKeyEvents(client *Client, root string, events []string) KeyEvents {
+	result := KeyEvents{
+		client:   client,
+		root:     root,
+		channel:  keyeventChannel(client) + "*",
+		pattern:  true,
+		keyevent: true,
+	}
+	if len(events) == 1 {
+		result.channel = keyeventChannel(client) + events[0]
+		result.pattern = false
+	}
+	if len(events) > 0 {
+		result = result.Only(events...)
+	}
+	return result
+}
+
+func keyspaceChannel(client *Client) string {
+	return "__keyspace@" + itoa(client.config.DBid) + "__:"
+}
+
+func keyeventChannel(client *Client) string {
+	return "__keyevent@" + itoa(client.config.DBid) + "__:"
+}
+
+//Events defines a subscription to the keyspace notifications of this key (e.g. to find out when it expires).
+//The key must belong to a Client or a Prefix, since the subscription needs a connection of its own;
+//within a Pipeline or Transaction, an error is sent to the error callback and the subscription never starts.
+//This is a lightweight function - does *not* involve network I/O
+func (this Key) Events() KeyEvents {
+	client, ok := this.client.(*Client)
+	if !ok {
+		this.client.errCallback(errors.New("Keyspace notifications need a Client to subscribe with"), "Events of "+this.key)
+		return KeyEvents{}
+	}
+	return newKeyEvents(client, "", this.key)
+}
+
+//Only narrows the events that get sent back down to the ones specified (e.g. only EventExpired).
+//Specifying no events at all sends back every event again
+func (this KeyEvents) Only(events ...string) KeyEvents {
+	if len(events) == 0 {
+		this.events = nil
+		return this
+	}
+	this.events = make(map[string]bool, len(events))
+	for _, event := range events {
+		this.events[event] = true
+	}
+	return this
+}
+
+//flags returns the notify-keyspace-events flags that the subscription needs:
+//"K" for keyspace channels or "E" for keyevent channels, along with the class of each event it's narrowed down to ("A" for every class)
+func (this KeyEvents) flags() string {
+	flags := "K"
+	if this.keyevent {
+		flags = "E"
+	}
+	if this.events == nil {
+		return flags + "A"
+	}
+	for event := range this.events {
+		class, ok := eventClasses[event]
+		if !ok {
+			//there's no telling which class an unknown event belongs to
+			return flags[:1] + "A"
+		}
+		if !strings.ContainsRune(flags, class) {
+			flags += string(class)
+		}
+	}
+	return flags
+}
+
+func (this KeyEvents) receive(action func(KeyEvent)) func(Message) {
+	return func(m Message) {
+		if !m.IsMessage() {
+			return
+		}
+		//keyspace channels are named after the key, and send the event; keyevent channels are the other way around
+		key, event := strings.TrimPrefix(m.Channel, keyspaceChannel(this.client)), m.Payload
+		if this.keyevent {
+			key, event = m.Payload, strings.TrimPrefix(m.Channel, keyeventChannel(this.client))
+		}
+		if !strings.HasPrefix(key, this.root) || (this.events != nil && !this.events[event]) {
+			return
+		}
+		action(KeyEvent{
+			Key:   strings.TrimPrefix(key, this.root),
+			Event: event,
+		})
+	}
+}
+
+//Subscribe makes sure that redis is sending out the notifications the subscription needs (adding to whatever it already sends out),
+//and calls the specified function whenever an event happens.
+//It returns a channel that allows you to know when the subscription has started succesfully listening (with the notifications turned on),
+//and a way to signal when you're done listening
+func (this KeyEvents) Subscribe(action func(KeyEvent)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	started := make(chan nothing, 1)
+	if this.client == nil {
+		close(started)
+		closed := subscription(nil)
+		return started, &closed
+	}
+
+	enabled := newServer(this.client).EnableKeyspaceEvents(this.flags())
+	sub := "subscribe"
+	if this.pattern {
+		sub = "psubscribe"
+	}
+	subscribed, closer := newChannel(this.client, this.channel).subscribe(this.receive(action), sub)
+	go func() {
+		defer close(started)
+		_, ok := <-enabled
+		if _, listening := <-subscribed; ok && listening {
+			started <- nothing{}
+		}
+	}()
+	return started, closer
+}
+
+//CONFIG GET command -
+//KeyspaceEvents returns the classes of keyspace notifications that redis is sending out (e.g. "KEA").
+//An empty string means that keyspace notifications are turned off
+func (this Server) KeyspaceEvents() <-chan string {
+	out := make(chan string, 1)
+	in := this.Config("notify-keyspace-events")
+	go func() {
+		defer close(out)
+		if config, ok := <-in; ok {
+			out <- config["notify-keyspace-events"]
+		}
+	}()
+	return out
+}
+
+//CONFIG SET command -
+//EnableKeyspaceEvents makes sure that redis is sending out the specified classes of keyspace notifications (e.g. "Kx" for the expiration of keys),
+//adding them to the ones that are already being sent out.
+//Redis is only reconfigured if some of the classes are missing.
+//Each Client makes these changes one at a time, so that subscriptions starting at the same time can't undo each other's classes
+func (this Server) EnableKeyspaceEvents(flags string) <-chan nothing {
+	out := make(chan nothing, 1)
+	go func() {
+		defer close(out)
+		if client, ok := this.client.(*Client); ok {
+			client.configuring.Lock()
+			defer client.configuring.Unlock()
+		}
+		current, ok := <-this.KeyspaceEvents()
+		if !ok {
+			return
+		}
+		//"A" stands for every class, so it's only missing if one of those classes is
+		has := expandEventClasses(current)
+		missing := ""
+		for _, flag := range expandEventClasses(flags) {
+			if !strings.ContainsRune(has, flag) && !strings.ContainsRune(missing, flag) {
+				missing += string(flag)
+			}
+		}
+		if missing != "" {
+			if _, ok := <-this.SetConfig("notify-keyspace-events", current+missing); !ok {
+				return
+			}
+		}
+		out <- nothing{}
+	}()
+	return out
+}
+
+func expandEventClasses(flags string) string {
+	return strings.Replace(flags, "A", allEventClasses, -1)
+}
@@ -0,0 +1,172 @@
+package redis
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyEvents(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	original := <-r.Server().KeyspaceEvents()
+	defer func() {
+		<-r.Server().SetConfig("notify-keyspace-events", original)
+	}()
+
+	//whatever redis was already sending out should be kept
+	<-r.Server().SetConfig("notify-keyspace-events", "Eg")
+
+	str := r.String("Test_KeyEvents_String")
+	expired := make(chan KeyEvent, 1)
+	started, closer := str.Events().Only(EventExpired).Subscribe(func(event KeyEvent) {
+		expired <- event
+	})
+	<-started
+	time.Sleep(50 * time.Millisecond)
+
+	if res := <-r.Server().KeyspaceEvents(); !strings.ContainsAny(res, "KA") || !strings.ContainsAny(res, "xA") ||
+		!strings.Contains(res, "E") || !strings.ContainsAny(res, "gA") {
+		t.Error("Keyspace events of expirations should have been added to the existing ones, not", res)
+	}
+	if res := <-r.Server().KeyspaceEvents(); strings.Contains(res, "A") || strings.Contains(res, "l") {
+		t.Error("Only the classes of events that were asked for should have been enabled, not", res)
+	}
+
+	<-str.Set("A")
+	<-str.ExpireIn(10 * time.Millisecond)
+	select {
+	case event := <-expired:
+		if event.Key != "Test_KeyEvents_String" || event.Event != EventExpired {
+			t.Error("Should have been told the key expired, not", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("Should have been told the key expired")
+	}
+	closer.Close()
+
+	p := r.Prefix("Test_KeyEvents_Prefix:")
+	events := make(chan KeyEvent, 16)
+	started, closer = p.AllKeyEvents().Subscribe(func(event KeyEvent) {
+		events <- event
+	})
+	<-started
+	time.Sleep(50 * time.Millisecond)
+
+	<-p.List("list").LeftPush("A")
+	<-p.Key("list").Delete()
+	<-r.String("Test_KeyEvents_Outside").Set("A")
+	for _, expected := range []KeyEvent{{"list", EventLPush}, {"list", EventDelete}} {
+		select {
+		case event := <-events:
+			if event != expected {
+				t.Error("Should have gotten", expected, "not", event)
+			}
+		case <-time.After(time.Second):
+			t.Error("Should have gotten", expected)
+		}
+	}
+	closer.Close()
+
+	expired = make(chan KeyEvent, 2)
+	started, closer = p.KeyEventsOf(EventExpired).Subscribe(func(event KeyEvent) {
+		expired <- event
+	})
+	<-started
+	time.Sleep(50 * time.Millisecond)
+
+	<-r.Key("Test_KeyEvents_Outside").ExpireIn(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	<-p.String("string").Set("A")
+	<-p.Key("string").ExpireIn(10 * time.Millisecond)
+	select {
+	case event := <-expired:
+		if event.Key != "string" || event.Event != EventExpired {
+			t.Error("Should have been told that the key within the namespace expired, not", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("Should have been told that the key within the namespace expired")
+	}
+	closer.Close()
+
+	<-r.Key("Test_KeyEvents_Outside").Delete()
+}
+
+func TestKeyEventFlags(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	if flags := r.Key("A").Events().Only(EventExpired).flags(); flags != "Kx" {
+		t.Error("Expirations of a key should need Kx, not", flags)
+	}
+	if flags := r.Prefix("A:").AllKeyEvents().flags(); flags != "KA" {
+		t.Error("Every keyspace event should need KA, not", flags)
+	}
+	if flags := r.KeyEventsOf(EventLPush, EventRPush).flags(); flags != "El" {
+		t.Error("List pushes should need El, not", flags)
+	}
+	if flags := r.KeyEventsOf("unknown").flags(); flags != "EA" {
+		t.Error("Unknown events should need every class, not", flags)
+	}
+	if flags := r.KeyEventsOf().flags(); flags != "EA" {
+		t.Error("Every keyevent should need EA, not", flags)
+	}
+	if flags := r.Key("A").Events().Only().flags(); flags != "KA" {
+		t.Error("Narrowing the events down to none at all should need every class, not", flags)
+	}
+
+	reported := make(chan error, 1)
+	r.SetErrorCallback(func(err error, s string) {
+		reported <- err
+	})
+	var events KeyEvents
+	r.Pipeline(func(e SafeExecutor) {
+		events = r.Key("A").Use(e).Events()
+	})
+	if <-reported == nil {
+		t.Error("Subscribing to the events of a key within a pipeline should fail")
+	}
+	started, _ := events.Subscribe(func(KeyEvent) {})
+	if _, ok := <-started; ok {
+		t.Error("A subscription without a Client should never start")
+	}
+}
+
+func TestConcurrentKeyEvents(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	original := <-r.Server().KeyspaceEvents()
+	defer func() {
+		<-r.Server().SetConfig("notify-keyspace-events", original)
+	}()
+	<-r.Server().SetConfig("notify-keyspace-events", "")
+
+	//each subscription adds its own classes to the setting, so none of them may be lost when they start at the same time
+	subscriptions := []KeyEvents{
+		r.Key("Test_KeyEvents_A").Events().Only(EventExpired),
+		r.Key("Test_KeyEvents_B").Events().Only(EventLPush),
+		r.KeyEventsOf(EventZAdd),
+		r.KeyEventsOf(EventHSet),
+	}
+	starts := make([]<-chan nothing, len(subscriptions))
+	for i, events := range subscriptions {
+		var closer io.Closer
+		starts[i], closer = events.Subscribe(func(KeyEvent) {})
+		defer closer.Close()
+	}
+	for _, start := range starts {
+		if _, ok := <-start; !ok {
+			t.Fatal("Every subscription should have started")
+		}
+	}
+
+	res := <-r.Server().KeyspaceEvents()
+	for _, class := range "KExlzh" {
+		if !strings.ContainsRune(res, class) {
+			t.Error("Every subscription's classes should have been enabled, but", string(class), "is missing from", res)
+		}
+	}
+}
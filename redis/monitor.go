@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//MonitorEntry is a single command that the server ran, as reported by MONITOR
+type MonitorEntry struct {
+	Time     time.Time
+	Database int
+
+	//ClientAddress is the address of the connection that ran the command ("lua" if it was run by a script)
+	ClientAddress string
+
+	//Arguments are the command and its arguments
+	Arguments []string
+}
+
+//parseMonitorEntry parses a line such as `1339518083.107412 [0 127.0.0.1:60866] "set" "key" "value"`
+func parseMonitorEntry(line string) (MonitorEntry, bool) {
+	entry := MonitorEntry{}
+
+	timestamp, rest, found := strings.Cut(line, " [")
+	if !found {
+		return entry, false
+	}
+	whole, fraction, _ := strings.Cut(timestamp, ".")
+	seconds, err := atoi(whole)
+	if err != nil {
+		return entry, false
+	}
+	microseconds, _ := atoi(fraction)
+	entry.Time = time.Unix(int64(seconds), int64(microseconds)*int64(time.Microsecond))
+
+	client, rest, found := strings.Cut(rest, "] ")
+	if !found {
+		return entry, false
+	}
+	db, address, _ := strings.Cut(client, " ")
+	entry.Database, _ = atoi(db)
+	entry.ClientAddress = address
+
+	entry.Arguments = parseQuotedArguments(rest)
+	return entry, len(entry.Arguments) > 0
+}
+
+//parseQuotedArguments splits up the double quoted (and escaped) arguments that MONITOR sends back
+func parseQuotedArguments(text string) []string {
+	result := []string{}
+	var current strings.Builder
+	quoted := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case !quoted:
+			if c == '"' {
+				quoted = true
+				current.Reset()
+			}
+		case c == '"':
+			quoted = false
+			result = append(result, current.String())
+		case c == '\\' && i+1 < len(text):
+			i++
+			switch text[i] {
+			case 'n':
+				current.WriteByte('\n')
+			case 'r':
+				current.WriteByte('\r')
+			case 't':
+				current.WriteByte('\t')
+			case 'a':
+				current.WriteByte('\a')
+			case 'b':
+				current.WriteByte('\b')
+			case 'x':
+				if i+2 < len(text) {
+					if b, err := strconv.ParseUint(text[i+1:i+3], 16, 8); err == nil {
+						current.WriteByte(byte(b))
+						i += 2
+						continue
+					}
+				}
+				current.WriteByte('x')
+			default:
+				current.WriteByte(text[i])
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	return result
+}
+
+//MONITOR command -
+//Monitor opens up a dedicated connection, and sends back every command the server runs through a channel until you signal that you're done.
+//(Warning - monitoring slows the server down considerably, so it should only be used while debugging)
+func (this *Client) Monitor() (entries <-chan MonitorEntry, finishSignaler io.Closer) {
+	out := make(chan MonitorEntry, messageBufferSize)
+	closer := make(chan bool, 1)
+	go func() {
+		defer close(out)
+		this.useNewConnection(func(conn *Connection) {
+			if _, ok := <-NilCommand(conn, "MONITOR"); !ok {
+				return
+			}
+
+			//redis never stops monitoring on its own, so closing the connection is the only way to stop reading
+			stopped := make(chan nothing)
+			finished := make(chan nothing)
+			defer close(finished)
+			go func() {
+				select {
+				case <-closer:
+					close(stopped)
+					conn.Close()
+				case <-finished:
+				}
+			}()
+
+			for {
+				r, err := getResponse(conn)
+				if err != nil {
+					select {
+					case <-stopped:
+					default:
+						this.errCallback(err, "MONITOR")
+					}
+					return
+				}
+				if entry, ok := parseMonitorEntry(r.val); ok {
+					select {
+					case out <- entry:
+					case <-stopped:
+						return
+					}
+				}
+			}
+		})
+	}()
+	subsc := (subscription)(closer)
+	return out, &subsc
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	entries, closer := r.Monitor()
+
+	//keep issuing a sentinel command until it shows up, so that we know the monitor is running
+	sentinel := r.String("Test_Monitor_Sentinel")
+	timeout := time.After(5 * time.Second)
+	for ready := false; !ready; {
+		<-sentinel.Get()
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				t.Fatal("Monitoring stopped before the sentinel command was seen")
+			}
+			ready = len(entry.Arguments) == 2 && entry.Arguments[1] == "Test_Monitor_Sentinel"
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("Should have seen the sentinel command while monitoring")
+		}
+	}
+
+	str := r.String("Test_Monitor_String")
+	<-str.Set("A \"quoted\"\nvalue")
+
+	timeout = time.After(time.Second)
+	found := false
+	for !found {
+		select {
+		case entry := <-entries:
+			if len(entry.Arguments) == 3 && entry.Arguments[1] == "Test_Monitor_String" {
+				found = true
+				if entry.Arguments[2] != "A \"quoted\"\nvalue" {
+					t.Error("Arguments weren't unescaped properly -", entry.Arguments)
+				}
+				if entry.Database != 0 || entry.ClientAddress == "" || time.Since(entry.Time) > time.Minute {
+					t.Error("Entry wasn't parsed properly -", entry)
+				}
+			}
+		case <-timeout:
+			t.Fatal("Should have seen the SET command while monitoring")
+		}
+	}
+
+	closer.Close()
+	for range entries {
+	}
+
+	<-str.Delete()
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errWholeDatabase   = errors.New("Namespace-wide changes can only be made within a Prefix, not to the whole database")
+	errNamespaceQueued = errors.New("Namespace-wide changes have to scan through the keys as they go, so they can't be queued up in a Pipeline or Transaction")
+)
+
+const (
+	namespaceBatchSize = 100
+)
+
+//deleteNamespaceSource deletes every key that fits a pattern in one go.
+//KEYS blocks redis while it runs, so this is only suitable for small namespaces
+const deleteNamespaceSource = `
+local keys = redis.call("KEYS", ARGV[1])
+for i = 1, #keys, 1000 do
+	redis.call("UNLINK", unpack(keys, i, math.min(i + 999, #keys)))
+end
+return #keys
+`
+
+//namespaceBatches walks through every key within a namespace, handing off the full names of the keys a batch at a time
+func namespaceBatches(p Prefix, each func([]string)) {
+	root := p.Root()
+	batch := make([]string, 0, namespaceBatchSize)
+	for key := range p.AllKeys(ScanOptions{Count: namespaceBatchSize}) {
+		batch = append(batch, root+key)
+		if len(batch) == namespaceBatchSize {
+			each(batch)
+			batch = make([]string, 0, namespaceBatchSize)
+		}
+	}
+	if len(batch) > 0 {
+		each(batch)
+	}
+}
+
+func namespaceSize(p Prefix) <-chan int {
+	out := make(chan int, 1)
+	go func() {
+		defer close(out)
+		count := 0
+		for range p.AllKeys(ScanOptions{Count: namespaceBatchSize}) {
+			count++
+		}
+		out <- count
+	}()
+	return out
+}
+
+func namespaceDelete(client *Client, p Prefix) <-chan int {
+	out := make(chan int, 1)
+	go func() {
+		defer close(out)
+		deleted := 0
+		namespaceBatches(p, func(keys []string) {
+			deleted += <-IntCommand(client, append([]string{"UNLINK"}, keys...)...)
+		})
+		out <- deleted
+	}()
+	return out
+}
+
+func namespaceDeleteAtomically(script Script, p Prefix) <-chan int {
+	return script.Args(escapeGlob(p.Root()) + "*").GetInt()
+}
+
+//namespaceRefused reports why a namespace-wide change wasn't made, and gives back a channel that never sends anything
+func namespaceRefused(client *Client, err error, command string) <-chan int {
+	out := make(chan int)
+	close(out)
+	client.errCallback(err, command)
+	return out
+}
+
+func namespaceExpire(client *Client, p Prefix, duration time.Duration) <-chan int {
+	out := make(chan int, 1)
+	go func() {
+		defer close(out)
+		expired := 0
+		namespaceBatches(p, func(keys []string) {
+			results := make([]<-chan bool, len(keys))
+			client.Pipeline(func(e SafeExecutor) {
+				for i, key := range keys {
+					results[i] = newKey(e, key).ExpireIn(duration)
+				}
+			})
+			for _, result := range results {
+				if <-result {
+					expired++
+				}
+			}
+		})
+		out <- expired
+	}()
+	return out
+}
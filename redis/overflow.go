@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+//OverflowPolicy decides what happens when a message arrives on a subscription whose buffer is already full
+type OverflowPolicy int
+
+const (
+	//OverflowBlock waits until there's room in the buffer, which stops anything else being read from the connection in the meantime.
+	//This is the default
+	OverflowBlock OverflowPolicy = iota
+
+	//OverflowDropNewest throws away the message that just arrived
+	OverflowDropNewest
+
+	//OverflowDropOldest throws away the oldest message in the buffer to make room for the one that just arrived
+	OverflowDropOldest
+
+	//OverflowDisconnect ends the subscription (once the buffered messages have been handled), and sends an error to the error callback
+	OverflowDisconnect
+)
+
+var errSlowConsumer = errors.New("Subscription buffer overflowed - messages weren't being handled quickly enough")
+
+//bufferPolicy is how a subscription buffers the messages that are waiting to be handled
+type bufferPolicy struct {
+	size    int
+	policy  OverflowPolicy
+	dropped *int64
+}
+
+func (this bufferPolicy) capacity() int {
+	if this.size <= 0 {
+		return messageBufferSize
+	}
+	return this.size
+}
+
+func (this bufferPolicy) drop() {
+	if this.dropped != nil {
+		atomic.AddInt64(this.dropped, 1)
+	}
+}
+
+//deliver puts a message in the buffer (or not) according to the policy.
+//It returns whether the subscription should carry on, and whether it should stop because the buffer overflowed
+func (this bufferPolicy) deliver(buffer chan Message, m Message, done <-chan nothing) (carryOn bool, overflowed bool) {
+	select {
+	case buffer <- m:
+		return true, false
+	case <-done:
+		return false, false
+	default:
+	}
+
+	switch this.policy {
+	case OverflowDropNewest:
+		this.drop()
+		return true, false
+	case OverflowDropOldest:
+		for {
+			select {
+			case buffer <- m:
+				return true, false
+			case <-done:
+				return false, false
+			default:
+			}
+			select {
+			case <-buffer:
+				this.drop()
+			default:
+			}
+		}
+	case OverflowDisconnect:
+		this.drop()
+		return false, true
+	}
+
+	select {
+	case buffer <- m:
+		return true, false
+	case <-done:
+		return false, false
+	}
+}
+
+//Buffered sets how many messages each subscription to this channel holds onto while they wait to be handled (64 by default),
+//and what happens when another message arrives once that many are waiting (OverflowBlock by default).
+//A handler that can't keep up eventually makes redis disconnect the client for exceeding its output buffer limit,
+//unless the messages are dropped (or the subscription is ended) first
+func (this Channel) Buffered(size int, policy OverflowPolicy) Channel {
+	this.buffer = bufferPolicy{
+		size:    size,
+		policy:  policy,
+		dropped: new(int64),
+	}
+	return this
+}
+
+//Dropped returns the number of messages that subscriptions to this channel have thrown away because their buffer was full.
+//Only the subscriptions made with the Channel returned by Buffered (or copies of it) are counted
+func (this Channel) Dropped() int64 {
+	if this.buffer.dropped == nil {
+		return 0
+	}
+	return atomic.LoadInt64(this.buffer.dropped)
+}
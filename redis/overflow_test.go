@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChannelOverflow(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	for _, policy := range []OverflowPolicy{OverflowDropNewest, OverflowDropOldest} {
+		channel := r.Channel("Test_Overflow_Channel").Buffered(1, policy)
+		received := make(chan string, 5)
+		release := make(chan nothing)
+		start, closer := channel.SubscribeMessages(func(m Message) {
+			if m.IsMessage() {
+				<-release
+				received <- m.Payload
+			}
+		})
+		<-start
+
+		for _, payload := range []string{"1", "2", "3", "4", "5"} {
+			<-channel.Publish(payload)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if dropped := channel.Dropped(); dropped != 3 {
+			t.Error("Should have dropped 3 messages, not", dropped, "with policy", policy)
+		}
+		close(release)
+
+		first, last := <-received, <-received
+		if first != "1" {
+			t.Error("The first message should have been handled before the buffer filled up, not", first)
+		}
+		if policy == OverflowDropNewest && last != "2" {
+			t.Error("Dropping the newest messages should have kept message 2, not", last)
+		}
+		if policy == OverflowDropOldest && last != "5" {
+			t.Error("Dropping the oldest messages should have kept message 5, not", last)
+		}
+		closer.Close()
+	}
+
+	lost := make(chan error, 1)
+	r.SetErrorCallback(func(err error, s string) {
+		if err == errSlowConsumer {
+			lost <- err
+		}
+	})
+	channel := r.Channel("Test_Overflow_Disconnect").Buffered(1, OverflowDisconnect)
+	release := make(chan nothing)
+	start, closer := channel.SubscribeMessages(func(m Message) {
+		if m.IsMessage() {
+			<-release
+		}
+	})
+	defer closer.Close()
+	<-start
+	for _, payload := range []string{"1", "2", "3"} {
+		<-channel.Publish(payload)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	select {
+	case <-lost:
+		if dropped := channel.Dropped(); dropped != 1 {
+			t.Error("Should have dropped the message that overflowed the buffer, not", dropped)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the subscription to be ended")
+	}
+}
+
+func TestSubscriberOverflow(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	channel := r.Channel("Test_Overflow_Subscriber")
+	for _, policy := range []OverflowPolicy{OverflowDropNewest, OverflowDropOldest} {
+		sub, err := r.BufferedSubscriber(1, policy)
+		if err != nil {
+			t.Fatal("Couldn't create the subscriber:", err)
+		}
+		received := make(chan string, 5)
+		release := make(chan nothing)
+		<-sub.Subscribe(func(payload string) {
+			<-release
+			received <- payload
+		}, channel)
+
+		for _, payload := range []string{"1", "2", "3", "4", "5"} {
+			<-channel.Publish(payload)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if dropped := sub.Dropped(); dropped != 3 {
+			t.Error("Should have dropped 3 messages, not", dropped, "with policy", policy)
+		}
+		close(release)
+
+		first, last := <-received, <-received
+		if first != "1" {
+			t.Error("The first message should have been handled before the buffer filled up, not", first)
+		}
+		if policy == OverflowDropNewest && last != "2" {
+			t.Error("Dropping the newest messages should have kept message 2, not", last)
+		}
+		if policy == OverflowDropOldest && last != "5" {
+			t.Error("Dropping the oldest messages should have kept message 5, not", last)
+		}
+		sub.Close()
+	}
+
+	lost := make(chan error, 1)
+	r.SetErrorCallback(func(err error, s string) {
+		if err == errSlowConsumer {
+			lost <- err
+		}
+	})
+	sub, err := r.BufferedSubscriber(1, OverflowDisconnect)
+	if err != nil {
+		t.Fatal("Couldn't create the subscriber:", err)
+	}
+	release := make(chan nothing)
+	<-sub.Subscribe(func(payload string) {
+		<-release
+	}, channel)
+	for _, payload := range []string{"1", "2", "3"} {
+		<-channel.Publish(payload)
+	}
+	select {
+	case <-lost:
+		if dropped := sub.Dropped(); dropped != 1 {
+			t.Error("Should have dropped the message that overflowed the buffer, not", dropped)
+		}
+		if sub.Close() == nil {
+			t.Error("The subscriber should already have been closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the subscriber to be closed")
+	}
+	close(release)
+}
@@ -1,5 +1,11 @@
 package redis
 
+import (
+	"io"
+	"iter"
+	"time"
+)
+
 type Prefix interface {
 	//Key creates a basic key; you probably won't use this directly very often.
 	//This is a lightweight function - does *not* involve network I/O
@@ -21,6 +27,15 @@ type Prefix interface {
 	//This is a lightweight function - does *not* involve network I/O
 	Bits(key string) Bits
 
+	//HyperLogLog creates the definition for a Redis String primitive that contains a HyperLogLog.
+	//This is a lightweight function - does *not* involve network I/O
+	HyperLogLog(key string) HyperLogLog
+
+	//BloomFilter creates the definition for a Redis String primitive that contains a bloom filter,
+	//sized to hold "expectedItems" with the specified false positive rate.
+	//This is a lightweight function - does *not* involve network I/O
+	BloomFilter(key string, expectedItems int, falsePositiveRate float64) BloomFilter
+
 	//Hash creates the definition for a basic Redis Hash primitive.
 	//This is a lightweight function - does *not* involve network I/O
 	Hash(key string) Hash
@@ -49,6 +64,14 @@ type Prefix interface {
 	//This is a lightweight function - does *not* involve network I/O
 	SortedIntSet(key string) SortedIntSet
 
+	//Geo creates the definition for a Redis ZSet primitive that is used as a geospatial index.
+	//This is a lightweight function - does *not* involve network I/O
+	Geo(key string) Geo
+
+	//Stream creates the definition for a Redis Stream primitive.
+	//This is a lightweight function - does *not* involve network I/O
+	Stream(key string) Stream
+
 	//Mutex creates a Mutex within redis.
 	//Warning - this is *not* a lightweight function - there is some network I/O involved in mutex initialization
 	Mutex(key string) Mutex
@@ -65,14 +88,98 @@ type Prefix interface {
 	//This is a lightweight function - does *not* involve network I/O
 	Channel(key string) Channel
 
+	//KeyEvents defines a subscription to the keyspace notifications of a single key within this namespace.
+	//This is a lightweight function - does *not* involve network I/O
+	KeyEvents(key string) KeyEvents
+
+	//AllKeyEvents defines a subscription to the keyspace notifications of every key within this namespace.
+	//This is a lightweight function - does *not* involve network I/O
+	AllKeyEvents() KeyEvents
+
+	//KeyEventsOf defines a subscription to the keyevent notifications of the events specified (e.g. every key within this namespace that expired),
+	//or of every event if none are specified.
+	//This is a lightweight function - does *not* involve network I/O
+	KeyEventsOf(events ...string) KeyEvents
+
 	//Prefix allows you to create a namespace for other redis primitives to help make sure there are no duplication conflicts.
 	//This is a lightweight function - does *not* involve network I/O
 	Prefix(key string) Prefix
+
+	//Use gives back the same namespace, but every object it creates (including the objects of nested namespaces) will run on a different executor,
+	//such as the one supplied by a Pipeline or Transaction.
+	//Mutexes, Semaphores, ReadWriteMutexes and Channel subscriptions still need to talk to redis directly, so they are not affected.
+	//This is a lightweight function - does *not* involve network I/O
+	Use(e SafeExecutor) Prefix
+
+	//SCAN command -
+	//Scan sends back every key within this namespace that fits the options (relative to the namespace) through a channel.
+	//Warning - this is *not* a lightweight function, it keeps using network I/O until every key has been sent back, or until you signal that you're done
+	Scan(options ScanOptions) (keys <-chan string, finishSignaler io.Closer)
+
+	//SCAN command -
+	//AllKeys is like Scan, but gives back an iterator to be used with range instead of a channel
+	AllKeys(options ScanOptions) iter.Seq[string]
+
+	//Root returns the full string that gets put in front of every key created within this namespace
+	Root() string
+
+	//Parent returns the namespace that this one was created within, or nil if this is the root namespace of a Client
+	Parent() Prefix
+
+	//Size returns the number of keys within this namespace (or within the database, for the Client's root namespace).
+	//Like Scan, it talks to redis directly, even on a namespace that was given a different executor by Use.
+	//Warning - this is *not* a lightweight function, it has to scan through the keys to count them
+	Size() <-chan int
+
+	//DeleteAll removes every key within this namespace, scanning through them and unlinking them a batch at a time;
+	//returns the number of keys removed.
+	//It has to talk to redis as it goes, so it can't be queued up in a Pipeline or Transaction - on a namespace that was given a different executor by Use,
+	//it sends an error to the error callback and removes nothing. It won't remove every key in the database from the Client's root namespace either.
+	//Warning - this is *not* a lightweight function, and keys created while it runs may not get removed
+	DeleteAll() <-chan int
+
+	//DeleteAllAtomically removes every key within this namespace in a single script;
+	//returns the number of keys removed.
+	//On a namespace that was given a different executor by Use, the script is issued on that executor (e.g. it becomes part of the Transaction).
+	//It won't remove every key in the database from the Client's root namespace - it sends an error to the error callback instead.
+	//Warning - this blocks redis while it looks through every key in the database, so it should only be used for small databases
+	DeleteAllAtomically() <-chan int
+
+	//ExpireAllIn sets every key within this namespace to expire after a specified duration;
+	//returns the number of keys that were set to expire.
+	//Like DeleteAll, it can't be queued up in a Pipeline or Transaction, and it won't touch every key in the database from the Client's root namespace
+	//(it sends an error to the error callback instead).
+	//Warning - this is *not* a lightweight function, and keys created while it runs may not get set to expire
+	ExpireAllIn(duration time.Duration) <-chan int
+
+	//PUBSUB CHANNELS command -
+	//ActiveChannels returns every channel within this namespace that fits the pattern (relative to the namespace) which has at least one subscriber.
+	//An empty pattern returns every active channel within the namespace
+	ActiveChannels(pattern string) <-chan []string
+
+	//PUBSUB NUMSUB command -
+	//SubscriberCounts returns the number of subscribers of each of the channels within this namespace
+	SubscriberCounts(channels ...string) <-chan map[string]int
+
+	//PUBSUB NUMPAT command -
+	//PatternCount returns the number of patterns that are being subscribed to.
+	//Redis doesn't keep track of which namespace a pattern is in, so this counts the patterns of every client
+	PatternCount() <-chan int
+
+	//PUBSUB SHARDCHANNELS command -
+	//ActiveShardChannels is like ActiveChannels, but for shard channels
+	ActiveShardChannels(pattern string) <-chan []string
+
+	//PUBSUB SHARDNUMSUB command -
+	//ShardSubscriberCounts is like SubscriberCounts, but for shard channels
+	ShardSubscriberCounts(channels ...string) <-chan map[string]int
 }
 
 type prefix struct {
-	parent Prefix
-	root   string
+	parent   Prefix
+	root     string
+	client   *Client      //the Client that the namespace ultimately belongs to, for anything that has to talk to redis directly
+	executor SafeExecutor //the executor given to the namespace by Use, or nil if it talks to redis directly
 }
 
 func (this *prefix) Key(key string) Key {
@@ -95,6 +202,14 @@ func (this *prefix) Bits(key string) Bits {
 	return this.parent.Bits(this.root + key)
 }
 
+func (this *prefix) HyperLogLog(key string) HyperLogLog {
+	return this.parent.HyperLogLog(this.root + key)
+}
+
+func (this *prefix) BloomFilter(key string, expectedItems int, falsePositiveRate float64) BloomFilter {
+	return this.parent.BloomFilter(this.root+key, expectedItems, falsePositiveRate)
+}
+
 func (this *prefix) Hash(key string) Hash {
 	return this.parent.Hash(this.root + key)
 }
@@ -123,6 +238,14 @@ func (this *prefix) SortedIntSet(key string) SortedIntSet {
 	return this.parent.SortedIntSet(this.root + key)
 }
 
+func (this *prefix) Geo(key string) Geo {
+	return this.parent.Geo(this.root + key)
+}
+
+func (this *prefix) Stream(key string) Stream {
+	return this.parent.Stream(this.root + key)
+}
+
 func (this *prefix) Mutex(key string) Mutex {
 	return this.parent.Mutex(this.root + key)
 }
@@ -139,13 +262,255 @@ func (this *prefix) Channel(key string) Channel {
 	return this.parent.Channel(this.root + key)
 }
 
+func (this *prefix) KeyEvents(key string) KeyEvents {
+	return newKeyEvents(this.client, this.Root(), key)
+}
+
+func (this *prefix) AllKeyEvents() KeyEvents {
+	return newPrefixKeyEvents(this.client, this.Root())
+}
+
+func (this *prefix) KeyEventsOf(events ...string) KeyEvents {
+	return newEventKeyEvents(this.client, this.Root(), events)
+}
+
 func (this *prefix) Prefix(key string) Prefix {
-	return newPrefix(this, key)
+	return newPrefix(this.client, this.executor, this, key)
+}
+
+func (this *prefix) Use(e SafeExecutor) Prefix {
+	return newPrefix(this.client, e, this.parent.Use(e), this.root)
+}
+
+func (this *prefix) Scan(options ScanOptions) (keys <-chan string, finishSignaler io.Closer) {
+	return seqChannel(this.AllKeys(options))
 }
 
-func newPrefix(parent Prefix, key string) Prefix {
+func (this *prefix) AllKeys(options ScanOptions) iter.Seq[string] {
+	return prefixedKeys(this.root, options, this.parent.AllKeys)
+}
+
+func (this *prefix) Root() string {
+	return this.parent.Root() + this.root
+}
+
+func (this *prefix) Parent() Prefix {
+	return this.parent
+}
+
+func (this *prefix) Size() <-chan int {
+	return namespaceSize(this)
+}
+
+func (this *prefix) DeleteAll() <-chan int {
+	if this.executor != nil {
+		return namespaceRefused(this.client, errNamespaceQueued, "DeleteAll "+this.Root())
+	}
+	return namespaceDelete(this.client, this)
+}
+
+func (this *prefix) DeleteAllAtomically() <-chan int {
+	if this.executor != nil {
+		return namespaceDeleteAtomically(this.client.Script(deleteNamespaceSource).Use(this.executor), this)
+	}
+	return namespaceDeleteAtomically(this.client.Script(deleteNamespaceSource), this)
+}
+
+func (this *prefix) ExpireAllIn(duration time.Duration) <-chan int {
+	if this.executor != nil {
+		return namespaceRefused(this.client, errNamespaceQueued, "ExpireAllIn "+this.Root())
+	}
+	return namespaceExpire(this.client, this, duration)
+}
+
+func (this *prefix) ActiveChannels(pattern string) <-chan []string {
+	return trimmedChannels(this.root, this.parent.ActiveChannels(prefixedPattern(this.root, pattern)))
+}
+
+func (this *prefix) SubscriberCounts(channels ...string) <-chan map[string]int {
+	return trimmedCounts(this.root, this.parent.SubscriberCounts(prefixedNames(this.root, channels)...))
+}
+
+func (this *prefix) PatternCount() <-chan int {
+	return this.parent.PatternCount()
+}
+
+func (this *prefix) ActiveShardChannels(pattern string) <-chan []string {
+	return trimmedChannels(this.root, this.parent.ActiveShardChannels(prefixedPattern(this.root, pattern)))
+}
+
+func (this *prefix) ShardSubscriberCounts(channels ...string) <-chan map[string]int {
+	return trimmedCounts(this.root, this.parent.ShardSubscriberCounts(prefixedNames(this.root, channels)...))
+}
+
+func newPrefix(client *Client, executor SafeExecutor, parent Prefix, key string) Prefix {
 	p := new(prefix)
 	p.parent = parent
 	p.root = key
+	p.client = client
+	p.executor = executor
 	return p
 }
+
+//executorPrefix is the root namespace of a client, with every object it creates running on a different executor
+type executorPrefix struct {
+	client   *Client
+	executor SafeExecutor
+}
+
+func (this *executorPrefix) Key(key string) Key {
+	return newKey(this.executor, key)
+}
+
+func (this *executorPrefix) String(key string) String {
+	return newString(this.executor, key)
+}
+
+func (this *executorPrefix) Integer(key string) Integer {
+	return newInteger(this.executor, key)
+}
+
+func (this *executorPrefix) Float(key string) Float {
+	return newFloat(this.executor, key)
+}
+
+func (this *executorPrefix) Bits(key string) Bits {
+	return newBits(this.executor, key)
+}
+
+func (this *executorPrefix) HyperLogLog(key string) HyperLogLog {
+	return newHyperLogLog(this.executor, key)
+}
+
+func (this *executorPrefix) BloomFilter(key string, expectedItems int, falsePositiveRate float64) BloomFilter {
+	return newBits(this.executor, key).BloomFilter(expectedItems, falsePositiveRate)
+}
+
+func (this *executorPrefix) Hash(key string) Hash {
+	return newHash(this.executor, key)
+}
+
+func (this *executorPrefix) List(key string) List {
+	return newList(this.executor, key)
+}
+
+func (this *executorPrefix) IntList(key string) IntList {
+	return newIntList(this.executor, key)
+}
+
+func (this *executorPrefix) Set(key string) Set {
+	return newSet(this.executor, key)
+}
+
+func (this *executorPrefix) IntSet(key string) IntSet {
+	return newIntSet(this.executor, key)
+}
+
+func (this *executorPrefix) SortedSet(key string) SortedSet {
+	return newSortedSet(this.executor, key)
+}
+
+func (this *executorPrefix) SortedIntSet(key string) SortedIntSet {
+	return newSortedIntSet(this.executor, key)
+}
+
+func (this *executorPrefix) Geo(key string) Geo {
+	return newGeo(this.executor, key)
+}
+
+func (this *executorPrefix) Stream(key string) Stream {
+	return newStream(this.executor, key)
+}
+
+//mutexes need to block on redis as they're created, so they can't be queued up in a pipeline
+func (this *executorPrefix) Mutex(key string) Mutex {
+	return this.client.Mutex(key)
+}
+
+func (this *executorPrefix) Semaphore(key string, count int) Mutex {
+	return this.client.Semaphore(key, count)
+}
+
+func (this *executorPrefix) ReadWriteMutex(key string, readers int) *ReadWriteMutex {
+	return this.client.ReadWriteMutex(key, readers)
+}
+
+//publishing can be done on the executor, but subscriptions still need their own connection from the client
+func (this *executorPrefix) Channel(key string) Channel {
+	return newChannel(this.client, key).Use(this.executor)
+}
+
+func (this *executorPrefix) KeyEvents(key string) KeyEvents {
+	return this.client.KeyEvents(key)
+}
+
+func (this *executorPrefix) AllKeyEvents() KeyEvents {
+	return this.client.AllKeyEvents()
+}
+
+func (this *executorPrefix) KeyEventsOf(events ...string) KeyEvents {
+	return this.client.KeyEventsOf(events...)
+}
+
+func (this *executorPrefix) Prefix(key string) Prefix {
+	return newPrefix(this.client, this.executor, this, key)
+}
+
+func (this *executorPrefix) Use(e SafeExecutor) Prefix {
+	return this.client.Use(e)
+}
+
+//scans need to keep talking to redis as they go, so they can't be queued up in a pipeline
+func (this *executorPrefix) Scan(options ScanOptions) (keys <-chan string, finishSignaler io.Closer) {
+	return this.client.Scan(options)
+}
+
+func (this *executorPrefix) AllKeys(options ScanOptions) iter.Seq[string] {
+	return this.client.AllKeys(options)
+}
+
+func (this *executorPrefix) Root() string {
+	return this.client.Root()
+}
+
+func (this *executorPrefix) Parent() Prefix {
+	return this.client.Parent()
+}
+
+//like scans, counting talks to redis directly
+func (this *executorPrefix) Size() <-chan int {
+	return this.client.Size()
+}
+
+//the root namespace covers the whole database, so the Client refuses to make namespace-wide changes to it
+func (this *executorPrefix) DeleteAll() <-chan int {
+	return this.client.DeleteAll()
+}
+
+func (this *executorPrefix) DeleteAllAtomically() <-chan int {
+	return this.client.DeleteAllAtomically()
+}
+
+func (this *executorPrefix) ExpireAllIn(duration time.Duration) <-chan int {
+	return this.client.ExpireAllIn(duration)
+}
+
+func (this *executorPrefix) ActiveChannels(pattern string) <-chan []string {
+	return pubsubChannels(this.executor, "CHANNELS", pattern)
+}
+
+func (this *executorPrefix) SubscriberCounts(channels ...string) <-chan map[string]int {
+	return pubsubCounts(this.executor, "NUMSUB", channels)
+}
+
+func (this *executorPrefix) PatternCount() <-chan int {
+	return IntCommand(this.executor, "PUBSUB", "NUMPAT")
+}
+
+func (this *executorPrefix) ActiveShardChannels(pattern string) <-chan []string {
+	return pubsubChannels(this.executor, "SHARDCHANNELS", pattern)
+}
+
+func (this *executorPrefix) ShardSubscriberCounts(channels ...string) <-chan map[string]int {
+	return pubsubCounts(this.executor, "SHARDNUMSUB", channels)
+}
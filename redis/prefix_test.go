@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrefixes(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_Prefix:")
+	inner := p.Prefix("Inner:")
+
+	a := p.String("A")
+	b := inner.String("B")
+	<-a.Delete()
+	<-b.Delete()
+
+	<-a.Set("A")
+	<-b.Set("B")
+
+	if res := <-r.String("Test_Prefix:A").Get(); res != "A" {
+		t.Error("Prefixed string should be A, not", res)
+	}
+	if res := <-r.String("Test_Prefix:Inner:B").Get(); res != "B" {
+		t.Error("Nested prefixed string should be B, not", res)
+	}
+}
+
+func TestPrefixUse(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_Prefix_Use:")
+	a := p.String("A")
+	b := p.Prefix("Inner:").String("B")
+	c := r.String("Test_Prefix_Use_C")
+	<-a.Delete()
+	<-b.Delete()
+	<-c.Delete()
+
+	result := r.Transaction(func(e SafeExecutor) {
+		p.Use(e).String("A").Set("A")
+		p.Use(e).Prefix("Inner:").String("B").Set("B")
+		r.Use(e).String("Test_Prefix_Use_C").Set("C")
+
+		if _, ok := <-a.Get(); ok {
+			t.Error("a should not be set yet")
+		}
+		if _, ok := <-b.Get(); ok {
+			t.Error("b should not be set yet")
+		}
+		if _, ok := <-c.Get(); ok {
+			t.Error("c should not be set yet")
+		}
+	})
+
+	if !result.Succeeded() {
+		t.Error("Transaction should have succeeded")
+	}
+	if res := <-a.Get(); res != "A" {
+		t.Error("a should be A, not", res)
+	}
+	if res := <-b.Get(); res != "B" {
+		t.Error("b should be B, not", res)
+	}
+	if res := <-c.Get(); res != "C" {
+		t.Error("c should be C, not", res)
+	}
+}
+
+func TestPrefixNamespace(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	user := r.Prefix("Test_Namespace:").Prefix("User:42:")
+	other := r.Prefix("Test_Namespace:").Prefix("User:43:")
+
+	if res := user.Root(); res != "Test_Namespace:User:42:" {
+		t.Error("Root should be Test_Namespace:User:42:, not", res)
+	}
+	if res := user.Parent().Root(); res != "Test_Namespace:" {
+		t.Error("Parent's root should be Test_Namespace:, not", res)
+	}
+	if user.Parent().Parent().Parent() != nil {
+		t.Error("Client's root namespace should not have a parent")
+	}
+
+	<-user.DeleteAll()
+	<-other.DeleteAll()
+	if res := <-user.Size(); res != 0 {
+		t.Error("Namespace should be empty, not have", res, "keys")
+	}
+
+	sets := make([]<-chan nothing, 250)
+	for i := range sets {
+		sets[i] = user.String("String" + itoa(i)).Set("A")
+	}
+	for _, set := range sets {
+		<-set
+	}
+	<-user.Prefix("Friends:").IntSet("Set").Add(43)
+	<-other.String("String").Set("B")
+
+	if res := <-user.Size(); res != 251 {
+		t.Error("Namespace should have 251 keys, not", res)
+	}
+
+	if res := <-user.ExpireAllIn(time.Hour); res != 251 {
+		t.Error("Should have set 251 keys to expire, not", res)
+	}
+	if res := <-user.String("String0").SecondsToLive(); res <= 0 {
+		t.Error("Key should be set to expire")
+	}
+	if res := <-other.String("String").SecondsToLive(); res != -1 {
+		t.Error("Key outside the namespace should not be set to expire, has a TTL of", res)
+	}
+
+	if res := <-user.DeleteAll(); res != 251 {
+		t.Error("Should have deleted 251 keys, not", res)
+	}
+	if res := <-user.Size(); res != 0 {
+		t.Error("Namespace should be empty, not have", res, "keys")
+	}
+	if res := <-other.String("String").Get(); res != "B" {
+		t.Error("Key outside the namespace should still be B, not", res)
+	}
+
+	<-user.String("A").Set("A")
+	<-user.String("B").Set("B")
+	if res := <-user.DeleteAllAtomically(); res != 2 {
+		t.Error("Should have atomically deleted 2 keys, not", res)
+	}
+	if res := <-other.String("String").Get(); res != "B" {
+		t.Error("Key outside the namespace should still be B, not", res)
+	}
+
+	//namespace-wide changes made through Use must not happen outside of the transaction
+	refused := make(chan string, 3)
+	r.SetErrorCallback(func(err error, command string) {
+		refused <- command
+	})
+	<-user.String("A").Set("A")
+	var deleted, expired, atomic <-chan int
+	r.Transaction(func(e SafeExecutor) {
+		deleted = user.Use(e).DeleteAll()
+		expired = user.Use(e).ExpireAllIn(time.Hour)
+		atomic = user.Use(e).DeleteAllAtomically()
+		if res := <-user.String("A").Get(); res != "A" {
+			t.Error("Key should not have been deleted before the transaction was sent, is", res)
+		}
+	})
+	if _, ok := <-deleted; ok {
+		t.Error("DeleteAll should have been refused within a transaction")
+	}
+	if _, ok := <-expired; ok {
+		t.Error("ExpireAllIn should have been refused within a transaction")
+	}
+	if res := <-atomic; res != 1 {
+		t.Error("Should have atomically deleted 1 key within the transaction, not", res)
+	}
+	if len(refused) != 2 {
+		t.Error("Both refusals should have been reported, not", len(refused))
+	}
+
+	//nor should they apply to the whole database
+	if _, ok := <-r.DeleteAll(); ok {
+		t.Error("DeleteAll should have been refused on the whole database")
+	}
+	if res := <-other.String("String").Get(); res != "B" {
+		t.Error("Key outside the namespace should still be B, not", res)
+	}
+	r.SetErrorCallback(func(e error, s string) {
+		t.Error(e.Error() + " - " + s)
+	})
+	<-other.DeleteAll()
+}
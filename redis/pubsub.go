@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"strings"
+)
+
+func pubsubChannels(e Executor, op, pattern string) <-chan []string {
+	if pattern == "" {
+		return SliceCommand(e, "PUBSUB", op)
+	}
+	return SliceCommand(e, "PUBSUB", op, pattern)
+}
+
+func pubsubCounts(e Executor, op string, channels []string) <-chan map[string]int {
+	return stringintMapChannel(MapCommand(e, append([]string{"PUBSUB", op}, channels...)...))
+}
+
+//prefixedPattern narrows a pattern down to the channels within a namespace
+func prefixedPattern(root, pattern string) string {
+	if pattern == "" {
+		pattern = "*"
+	}
+	return escapeGlob(root) + pattern
+}
+
+func prefixedNames(root string, names []string) []string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = root + name
+	}
+	return result
+}
+
+//trimmedChannels makes the channel names relative to a namespace
+func trimmedChannels(root string, in <-chan []string) <-chan []string {
+	out := make(chan []string, 1)
+	go func() {
+		defer close(out)
+		if channels, ok := <-in; ok {
+			for i, channel := range channels {
+				channels[i] = strings.TrimPrefix(channel, root)
+			}
+			out <- channels
+		}
+	}()
+	return out
+}
+
+//trimmedCounts makes the channel names relative to a namespace
+func trimmedCounts(root string, in <-chan map[string]int) <-chan map[string]int {
+	out := make(chan map[string]int, 1)
+	go func() {
+		defer close(out)
+		if counts, ok := <-in; ok {
+			result := make(map[string]int, len(counts))
+			for channel, count := range counts {
+				result[strings.TrimPrefix(channel, root)] = count
+			}
+			out <- result
+		}
+	}()
+	return out
+}
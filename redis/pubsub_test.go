@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPubSubIntrospection(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_PubSub:")
+	channel := p.Channel("Channel")
+	start, closer := channel.Subscribe(func(string) {})
+	defer closer.Close()
+	<-start
+
+	if active := <-r.ActiveChannels("Test_PubSub:*"); len(active) != 1 || active[0] != "Test_PubSub:Channel" {
+		t.Error("ActiveChannels should have found Test_PubSub:Channel, not", active)
+	}
+	if active := <-p.ActiveChannels(""); len(active) != 1 || active[0] != "Channel" {
+		t.Error("Prefix.ActiveChannels should have found Channel, not", active)
+	}
+	if counts := <-p.SubscriberCounts("Channel", "Other"); counts["Channel"] != 1 || counts["Other"] != 0 {
+		t.Error("Prefix.SubscriberCounts should have counted 1 subscriber, not", counts)
+	}
+	if count := <-channel.SubscriberCount(); count != 1 {
+		t.Error("Channel.SubscriberCount should be 1, not", count)
+	}
+	if _, ok := <-r.PatternCount(); !ok {
+		t.Error("PatternCount failed")
+	}
+}
+
+func TestShardChannels(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	channel := r.Channel("Test_Shard_Channel")
+	messages := make(chan Message, 1)
+	start, closer := channel.ShardSubscribeMessages(func(m Message) {
+		if m.IsMessage() {
+			messages <- m
+		}
+	})
+	defer closer.Close()
+	<-start
+
+	if active := <-r.ActiveShardChannels("Test_Shard_*"); len(active) != 1 || active[0] != "Test_Shard_Channel" {
+		t.Error("ActiveShardChannels should have found Test_Shard_Channel, not", active)
+	}
+	if count := <-channel.ShardSubscriberCount(); count != 1 {
+		t.Error("ShardSubscriberCount should be 1, not", count)
+	}
+	if count := <-channel.ShardPublish("Shard Test"); count != 1 {
+		t.Error("ShardPublish should have reached 1 subscriber, not", count)
+	}
+
+	select {
+	case m := <-messages:
+		if m.Kind != KindShardMessage || m.Channel != "Test_Shard_Channel" || m.Payload != "Shard Test" {
+			t.Error("Received the wrong message:", m)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the shard message")
+	}
+
+	sub, err := r.Subscriber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	received := make(chan string, 1)
+	<-sub.ShardSubscribe(func(message string) { received <- message }, channel)
+	if shards := sub.ShardChannels(); len(shards) != 1 || shards[0] != "Test_Shard_Channel" {
+		t.Error("ShardChannels should be [Test_Shard_Channel], not", shards)
+	}
+	channel.ShardPublish("Subscriber Test")
+	select {
+	case message := <-received:
+		if message != "Subscriber Test" {
+			t.Error("Subscriber received the wrong message:", message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for the Subscriber's shard message")
+	}
+	<-sub.ShardUnsubscribe(channel)
+}
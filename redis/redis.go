@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"iter"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -51,9 +54,11 @@ func getError(rec interface{}) error {
 
 // The Client is the base for all communication to and from Redis
 type Client struct {
-	nextID       int
+	nextID       int64
 	isClosed     bool
 	pool         chan *Connection // 	a semaphore of connections to draw from when multiple threads want to connect
+	draining     *sync.Mutex      //	held while taking every connection out of the pool, so that two callers can't each end up with part of it
+	configuring  *sync.Mutex      //	held while changing a server setting based on what it was, so that two changes can't undo each other
 	config       Config           //	connection details, so we know how to connect to redis
 	fErrCallback errCallbackFunc  //	a callback function - since we operate in a separate goroutine, we can't return an error, instead we call this function sending it the error, and the command we tried to issue
 }
@@ -72,6 +77,8 @@ func New(config Config) (r *Client, e error) {
 
 	this := new(Client)
 	this.config = config
+	this.draining = new(sync.Mutex)
+	this.configuring = new(sync.Mutex)
 
 	this.pool = make(chan *Connection, config.ConnectionCount)
 	for i := 0; i < config.ConnectionCount; i++ {
@@ -145,7 +152,7 @@ func (this *Client) newConnection() (*Connection, error) {
 		return nil, err
 	}
 
-	c := &Connection{conn, this.nextID, this}
+	c := &Connection{conn, int(atomic.AddInt64(&this.nextID, 1) - 1), this}
 
 	if this.config.Password != "" {
 		<-NilCommand(c, "AUTH", this.config.Password)
@@ -153,7 +160,6 @@ func (this *Client) newConnection() (*Connection, error) {
 	if this.config.DBid != 0 {
 		<-NilCommand(c, "SELECT", itoa(this.config.DBid))
 	}
-	this.nextID++
 	return c, nil
 }
 
@@ -170,10 +176,35 @@ func (this *Client) useConnection(callback func(*Connection)) {
 	callback(conn)
 }
 
+//useAllConnections takes every connection out of the pool (waiting for any that are in use), and then runs the callback on each one.
+//Only one caller at a time can do so - otherwise each could be left waiting on the connections that the other took
+func (this *Client) useAllConnections(callback func(*Connection)) {
+	if this.isClosed {
+		return
+	}
+	this.draining.Lock()
+	defer this.draining.Unlock()
+
+	conns := make([]*Connection, 0, this.config.ConnectionCount)
+	defer func() {
+		for _, conn := range conns {
+			this.pool <- conn
+		}
+	}()
+
+	for len(conns) < this.config.ConnectionCount {
+		conns = append(conns, <-this.pool)
+	}
+	for _, conn := range conns {
+		callback(conn)
+	}
+}
+
 func (this *Client) useNewConnection(callback func(*Connection)) {
 	conn, err := this.newConnection()
 	if err != nil {
 		this.errCallback(err, "new connection")
+		return
 	}
 
 	defer func() {
@@ -213,6 +244,18 @@ func (this *Client) Bits(key string) Bits {
 	return newBits(this, key)
 }
 
+//Creates a HyperLogLog object.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) HyperLogLog(key string) HyperLogLog {
+	return newHyperLogLog(this, key)
+}
+
+//Creates a BloomFilter object, sized to hold "expectedItems" with the specified false positive rate.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) BloomFilter(key string, expectedItems int, falsePositiveRate float64) BloomFilter {
+	return newBits(this, key).BloomFilter(expectedItems, falsePositiveRate)
+}
+
 //Creates a Hash object.
 //(This is a lightweight function - does *not* involve network I/O)
 func (this *Client) Hash(key string) Hash {
@@ -255,6 +298,18 @@ func (this *Client) SortedIntSet(key string) SortedIntSet {
 	return newSortedIntSet(this, key)
 }
 
+//Creates a Geo Object.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) Geo(key string) Geo {
+	return newGeo(this, key)
+}
+
+//Creates a Stream Object.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) Stream(key string) Stream {
+	return newStream(this, key)
+}
+
 //Creates a Mutex Object.
 //(Warning - this is *not* a lightweight function - there is some network I/O involved in mutex initialization)
 func (this *Client) Mutex(key string) Mutex {
@@ -279,8 +334,134 @@ func (this *Client) Channel(key string) Channel {
 	return newChannel(this, key)
 }
 
+//Creates a KeyEvents Object, which subscribes to the keyspace notifications of a single key.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) KeyEvents(key string) KeyEvents {
+	return newKeyEvents(this, "", key)
+}
+
+//Creates a KeyEvents Object, which subscribes to the keyspace notifications of every key in the database.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) AllKeyEvents() KeyEvents {
+	return newPrefixKeyEvents(this, "")
+}
+
+//Creates a KeyEvents Object, which subscribes to the keyevent notifications of the events specified (e.g. every key that expired),
+//or of every event if none are specified.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) KeyEventsOf(events ...string) KeyEvents {
+	return newEventKeyEvents(this, "", events)
+}
+
+//Creates a Script Object from Lua source code.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) Script(source string) Script {
+	return newScript(this, source)
+}
+
+//Creates a Functions Object, which manages redis function libraries and the functions within them.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) Functions() Functions {
+	return newFunctions(this)
+}
+
+//Creates a Server Object, which administers the redis server itself.
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) Server() Server {
+	return newServer(this)
+}
+
 //Creates a Prefix Object, which helps namespace other Redis Objects.
 //(This is a lightweight function - does *not* involve network I/O)
 func (this *Client) Prefix(key string) Prefix {
-	return newPrefix(this, key)
+	return newPrefix(this, nil, this, key)
+}
+
+//Use gives back the root namespace of this Client, with every object it creates running on a different executor
+//(typically the one supplied by a Pipeline or Transaction).
+//(This is a lightweight function - does *not* involve network I/O)
+func (this *Client) Use(e SafeExecutor) Prefix {
+	return &executorPrefix{
+		client:   this,
+		executor: e,
+	}
+}
+
+//SCAN command -
+//Scan sends back every key in the database that fits the options through a channel.
+//(Warning - this is *not* a lightweight function - it keeps using network I/O until every key has been sent back, or until you signal that you're done)
+func (this *Client) Scan(options ScanOptions) (keys <-chan string, finishSignaler io.Closer) {
+	return seqChannel(this.AllKeys(options))
+}
+
+//SCAN command -
+//AllKeys is like Scan, but gives back an iterator to be used with range instead of a channel
+func (this *Client) AllKeys(options ScanOptions) iter.Seq[string] {
+	return scanSeq(this, []string{"SCAN"}, options)
+}
+
+//Root returns the string that gets put in front of every key created directly from the Client, which is always empty
+func (this *Client) Root() string {
+	return ""
+}
+
+//Parent returns the namespace that the Client's root namespace was created within, which is always nil
+func (this *Client) Parent() Prefix {
+	return nil
+}
+
+//DBSIZE command -
+//Size returns the number of keys in the database
+func (this *Client) Size() <-chan int {
+	return IntCommand(this, "DBSIZE")
+}
+
+//DeleteAll only removes the keys within a namespace created with Prefix -
+//the Client's root namespace is the whole database, so it sends an error to the error callback instead
+func (this *Client) DeleteAll() <-chan int {
+	return namespaceRefused(this, errWholeDatabase, "DeleteAll")
+}
+
+//DeleteAllAtomically only removes the keys within a namespace created with Prefix -
+//the Client's root namespace is the whole database, so it sends an error to the error callback instead
+func (this *Client) DeleteAllAtomically() <-chan int {
+	return namespaceRefused(this, errWholeDatabase, "DeleteAllAtomically")
+}
+
+//ExpireAllIn only sets the keys within a namespace created with Prefix to expire -
+//the Client's root namespace is the whole database, so it sends an error to the error callback instead
+func (this *Client) ExpireAllIn(duration time.Duration) <-chan int {
+	return namespaceRefused(this, errWholeDatabase, "ExpireAllIn")
+}
+
+//PUBSUB CHANNELS command -
+//ActiveChannels returns every channel that fits the pattern (or every channel, if the pattern is empty) which has at least one subscriber.
+//Pattern subscriptions aren't counted
+func (this *Client) ActiveChannels(pattern string) <-chan []string {
+	return pubsubChannels(this, "CHANNELS", pattern)
+}
+
+//PUBSUB NUMSUB command -
+//SubscriberCounts returns the number of subscribers of each of the channels.
+//Pattern subscriptions aren't counted
+func (this *Client) SubscriberCounts(channels ...string) <-chan map[string]int {
+	return pubsubCounts(this, "NUMSUB", channels)
+}
+
+//PUBSUB NUMPAT command -
+//PatternCount returns the number of patterns that are being subscribed to, across every client
+func (this *Client) PatternCount() <-chan int {
+	return IntCommand(this, "PUBSUB", "NUMPAT")
+}
+
+//PUBSUB SHARDCHANNELS command -
+//ActiveShardChannels returns every shard channel that fits the pattern (or every shard channel, if the pattern is empty) which has at least one subscriber
+func (this *Client) ActiveShardChannels(pattern string) <-chan []string {
+	return pubsubChannels(this, "SHARDCHANNELS", pattern)
+}
+
+//PUBSUB SHARDNUMSUB command -
+//ShardSubscriberCounts returns the number of subscribers of each of the shard channels
+func (this *Client) ShardSubscriberCounts(channels ...string) <-chan map[string]int {
+	return pubsubCounts(this, "SHARDNUMSUB", channels)
 }
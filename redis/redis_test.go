@@ -38,6 +38,10 @@ func TestBadCommands(t *testing.T) {
 
 	s := r.String("ErrorTest")
 
+	reported := make(chan string, 1)
+	r.SetErrorCallback(func(e error, s string) {
+		reported <- s
+	})
 	var ch, ch2 <-chan nothing
 	r.Pipeline(func(e SafeExecutor) {
 		ch = NilCommand(e, "INVALIDCOMAND")
@@ -49,6 +53,14 @@ func TestBadCommands(t *testing.T) {
 	if _, ok := <-ch2; !ok {
 		t.Error("Second command should still work fine")
 	}
+	select {
+	case command := <-reported:
+		if command != "INVALIDCOMAND" {
+			t.Error("The error should be reported along with the failed command, not", command)
+		}
+	default:
+		t.Error("Using an invalid command within a pipeline should cause an error")
+	}
 
 	if res := <-s.Get(); res != "Test Test" {
 		t.Error("Should have gotten 'Test Test', not ", res)
@@ -0,0 +1,210 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//rpcEnvelope is what gets published for a request, and for its reply
+type rpcEnvelope struct {
+	ID      string `json:"id"`
+	ReplyTo string `json:"reply_to,omitempty"`
+	Payload string `json:"payload"`
+	Error   string `json:"error,omitempty"`
+}
+
+//A Caller makes requests to the servers listening on channels (with Serve), and waits for their replies.
+//Every reply comes back on a single channel that only this Caller listens to
+type Caller struct {
+	client     *Client
+	subscriber *Subscriber
+	replyTo    string
+	nextID     uint64
+	done       chan nothing
+
+	lock   sync.Mutex
+	calls  map[string]chan<- rpcEnvelope
+	closed bool
+}
+
+//Caller opens up a dedicated connection to receive the replies to requests.
+//(Warning - this is *not* a lightweight function - it opens up a new connection to redis)
+func (this *Client) Caller() (*Caller, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	subscriber, err := this.Subscriber()
+	if err != nil {
+		return nil, err
+	}
+
+	caller := &Caller{
+		client:     this,
+		subscriber: subscriber,
+		replyTo:    "rpc:reply:" + hex.EncodeToString(token),
+		done:       make(chan nothing),
+		calls:      make(map[string]chan<- rpcEnvelope),
+	}
+	if _, ok := <-subscriber.SubscribeMessages(caller.receive, newChannel(this, caller.replyTo)); !ok {
+		subscriber.Close()
+		return nil, errors.New("Couldn't subscribe to " + caller.replyTo)
+	}
+	return caller, nil
+}
+
+//Call publishes the request on the channel, and sends back the reply from whichever server handled it.
+//If there aren't any servers listening, the server's handler fails, or there's no reply within the timeout,
+//the error is sent to the Client's error callback and the channel is closed without a value
+func (this *Caller) Call(channel Channel, request string, timeout time.Duration) <-chan string {
+	out := make(chan string, 1)
+	id := strconv.FormatUint(atomic.AddUint64(&this.nextID, 1), 10)
+	replies := make(chan rpcEnvelope, 1)
+
+	this.lock.Lock()
+	if this.closed {
+		this.lock.Unlock()
+		close(out)
+		return out
+	}
+	this.calls[id] = replies
+	this.lock.Unlock()
+
+	data, err := json.Marshal(rpcEnvelope{ID: id, ReplyTo: this.replyTo, Payload: request})
+	if err != nil {
+		this.forget(id)
+		this.client.errCallback(err, "RPC call to "+channel.key)
+		close(out)
+		return out
+	}
+	published := channel.Publish(string(data))
+
+	go func() {
+		defer close(out)
+		defer this.forget(id)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		if count, ok := <-published; !ok {
+			return
+		} else if count == 0 {
+			this.client.errCallback(errors.New("Nothing is serving requests"), "RPC call to "+channel.key)
+			return
+		}
+
+		select {
+		case reply := <-replies:
+			if reply.Error != "" {
+				this.client.errCallback(errors.New(reply.Error), "RPC call to "+channel.key)
+				return
+			}
+			out <- reply.Payload
+		case <-timer.C:
+			this.client.errCallback(errors.New("No reply within "+timeout.String()), "RPC call to "+channel.key)
+		case <-this.done:
+		}
+	}()
+	return out
+}
+
+func (this *Caller) forget(id string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	delete(this.calls, id)
+}
+
+//receive hands each reply to the call that is waiting for it; replies that arrive after their call has given up are ignored
+func (this *Caller) receive(m Message) {
+	if !m.IsMessage() {
+		return
+	}
+	reply := rpcEnvelope{}
+	if err := json.Unmarshal([]byte(m.Payload), &reply); err != nil {
+		this.client.errCallback(err, "Decoding RPC reply")
+		return
+	}
+
+	this.lock.Lock()
+	replies, ok := this.calls[reply.ID]
+	delete(this.calls, reply.ID)
+	this.lock.Unlock()
+	if ok {
+		replies <- reply
+	}
+}
+
+//Close stops waiting for replies (abandoning any calls still in progress), and closes the connection
+func (this *Caller) Close() error {
+	this.lock.Lock()
+	if this.closed {
+		this.lock.Unlock()
+		return errors.New("Already closed this caller")
+	}
+	this.closed = true
+	close(this.done)
+	this.lock.Unlock()
+	return this.subscriber.Close()
+}
+
+//Call makes a single request to the servers listening on this channel, and sends back the reply.
+//(Warning - this opens up a new connection to redis for every call; use a Caller to make lots of them)
+func (this Channel) Call(request string, timeout time.Duration) <-chan string {
+	out := make(chan string, 1)
+	go func() {
+		defer close(out)
+		caller, err := this.client.Caller()
+		if err != nil {
+			this.client.errCallback(err, "RPC call to "+this.key)
+			return
+		}
+		defer caller.Close()
+		if reply, ok := <-caller.Call(this, request, timeout); ok {
+			out <- reply
+		}
+	}()
+	return out
+}
+
+//Serve calls the handler with every request published on this channel (by a Caller), and publishes whatever it returns as the reply.
+//Each request is handled in its own goroutine; if the handler returns an error, the caller is sent the error instead.
+//It returns a channel that allows you to know when the server has started succesfully listening,
+//and a way to signal when you're done serving requests
+func (this Channel) Serve(handler func(request string) (string, error)) (startSignal <-chan nothing, finishSignaler io.Closer) {
+	return this.SubscribeMessages(func(m Message) {
+		if !m.IsMessage() {
+			return
+		}
+		request := rpcEnvelope{}
+		if err := json.Unmarshal([]byte(m.Payload), &request); err != nil || request.ReplyTo == "" {
+			if err == nil {
+				err = errors.New("Request has nowhere to send the reply")
+			}
+			this.client.errCallback(err, "Decoding RPC request on "+m.Channel)
+			return
+		}
+
+		go func() {
+			reply := rpcEnvelope{ID: request.ID}
+			payload, err := handler(request.Payload)
+			if err != nil {
+				reply.Error = err.Error()
+			} else {
+				reply.Payload = payload
+			}
+			data, err := json.Marshal(reply)
+			if err != nil {
+				this.client.errCallback(err, "Encoding RPC reply")
+				return
+			}
+			<-IntCommand(this.Key.client, "PUBLISH", request.ReplyTo, string(data))
+		}()
+	})
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRPC(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	failures := make(chan error, 3)
+	r.SetErrorCallback(func(err error, s string) {
+		failures <- err
+	})
+
+	channel := r.Channel("Test_RPC")
+	start, closer := channel.Serve(func(request string) (string, error) {
+		switch request {
+		case "fail":
+			return "", errors.New("Failed on purpose")
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+		return strings.ToUpper(request), nil
+	})
+	defer closer.Close()
+	<-start
+
+	caller, err := r.Caller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caller.Close()
+
+	first, second := caller.Call(channel, "hello", time.Second), caller.Call(channel, "world", time.Second)
+	if reply := <-first; reply != "HELLO" {
+		t.Error("Should have replied HELLO, not", reply)
+	}
+	if reply := <-second; reply != "WORLD" {
+		t.Error("Should have replied WORLD, not", reply)
+	}
+	if reply := <-channel.Call("once", time.Second); reply != "ONCE" {
+		t.Error("Should have replied ONCE, not", reply)
+	}
+
+	if _, ok := <-caller.Call(channel, "fail", time.Second); ok {
+		t.Error("A failed request shouldn't have a reply")
+	} else if err := <-failures; err.Error() != "Failed on purpose" {
+		t.Error("Should have been sent the handler's error, not", err)
+	}
+
+	if _, ok := <-caller.Call(channel, "slow", 50*time.Millisecond); ok {
+		t.Error("A slow request should have timed out")
+	} else if err := <-failures; !strings.HasPrefix(err.Error(), "No reply") {
+		t.Error("Should have been told the request timed out, not", err)
+	}
+	//the late reply is ignored
+	time.Sleep(300 * time.Millisecond)
+
+	if _, ok := <-caller.Call(r.Channel("Test_RPC_Nobody"), "hello", time.Second); ok {
+		t.Error("A request to a channel without a server shouldn't have a reply")
+	} else if err := <-failures; !strings.HasPrefix(err.Error(), "Nothing") {
+		t.Error("Should have been told nothing was serving requests, not", err)
+	}
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"io"
+	"iter"
+	"strings"
+)
+
+const (
+	scanBufferSize = 64
+)
+
+//ScanOptions narrows down what gets returned while iterating over a collection with a cursor.
+//The zero value returns everything, letting redis decide how much to send back at a time.
+//
+//Scans can't be done within a Pipeline or Transaction, since they need to keep talking to redis as they go
+type ScanOptions struct {
+	//Match only returns items that fit the glob-style pattern
+	Match string
+
+	//Count hints at how many items redis should look at with each call
+	Count int
+
+	//Type only returns keys of the specified type (string, list, set, zset, hash or stream); only used when scanning keys
+	Type string
+}
+
+//args builds the arguments that follow the command; "keys" is whether the whole keyspace is being scanned, since only SCAN accepts a TYPE
+func (this ScanOptions) args(cursor string, keys bool) []string {
+	result := []string{cursor}
+	if this.Match != "" {
+		result = append(result, "MATCH", this.Match)
+	}
+	if this.Count > 0 {
+		result = append(result, "COUNT", itoa(this.Count))
+	}
+	if this.Type != "" && keys {
+		result = append(result, "TYPE", this.Type)
+	}
+	return result
+}
+
+//HashEntry is a single field of a Hash, along with its value
+type HashEntry struct {
+	Field string
+	Value string
+}
+
+//ScoredMember is a single member of a SortedSet, along with its score
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+//ScoredIntMember is a single member of a SortedIntSet, along with its score
+type ScoredIntMember struct {
+	Member int
+	Score  float64
+}
+
+//scan walks through a cursor-based command, handing off each batch of results until there are no more, or until "each" returns false
+func scan(e Executor, command []string, options ScanOptions, each func([]string) bool) {
+	cursor := "0"
+	keys := strings.EqualFold(command[0], "SCAN")
+	for {
+		args := append(append([]string{}, command...), options.args(cursor, keys)...)
+		r, ok := <-responseChannel(e, args...)
+		if !ok || len(r.subresponses) != 2 {
+			return
+		}
+		cursor = r.subresponses[0].value()
+		if !each(r.subresponses[1].values()) || cursor == "0" {
+			return
+		}
+	}
+}
+
+func scanSeq(e Executor, command []string, options ScanOptions) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		scan(e, command, options, func(items []string) bool {
+			for _, item := range items {
+				if !yield(item) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+func scanPairSeq(e Executor, command []string, options ScanOptions) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		scan(e, command, options, func(items []string) bool {
+			for i := 0; i+1 < len(items); i += 2 {
+				if !yield(items[i], items[i+1]) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+//escapeGlob makes sure that a literal string (such as the root of a Prefix) can't be mistaken for part of a pattern
+func escapeGlob(literal string) string {
+	var result strings.Builder
+	for _, r := range literal {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			result.WriteRune('\\')
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+//prefixedKeys narrows a scan down to the keys beginning with "root", and strips "root" from each key it finds
+func prefixedKeys(root string, options ScanOptions, scanner func(ScanOptions) iter.Seq[string]) iter.Seq[string] {
+	if options.Match == "" {
+		options.Match = "*"
+	}
+	options.Match = escapeGlob(root) + options.Match
+	return func(yield func(string) bool) {
+		for key := range scanner(options) {
+			if !yield(strings.TrimPrefix(key, root)) {
+				return
+			}
+		}
+	}
+}
+
+//seqChannel sends back everything an iterator gives through a channel, until it runs out or until the finishSignaler is closed.
+//Stopping early ends the range over the iterator, so that it doesn't go on to talk to redis for anything more
+func seqChannel[T any](seq iter.Seq[T]) (<-chan T, io.Closer) {
+	out := make(chan T, scanBufferSize)
+	closer := make(chan bool, 1)
+	go func() {
+		defer close(out)
+		for item := range seq {
+			select {
+			case <-closer:
+				return
+			default:
+			}
+			select {
+			case out <- item:
+			case <-closer:
+				return
+			}
+		}
+	}()
+	subsc := (subscription)(closer)
+	return out, &subsc
+}
+
+func seq2Channel[K, V, T any](seq iter.Seq2[K, V], combine func(K, V) T) (<-chan T, io.Closer) {
+	return seqChannel(func(yield func(T) bool) {
+		for k, v := range seq {
+			if !yield(combine(k, v)) {
+				return
+			}
+		}
+	})
+}
+
+//intSeq skips over anything that isn't an integer
+func intSeq(seq iter.Seq[string]) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for item := range seq {
+			if i, err := atoi(item); err == nil && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+//scoreSeq converts the scores of a zset into floats, skipping anything that isn't a number
+func scoreSeq(seq iter.Seq2[string, string]) iter.Seq2[string, float64] {
+	return func(yield func(string, float64) bool) {
+		for member, score := range seq {
+			if f, err := atof(score); err == nil && !yield(member, f) {
+				return
+			}
+		}
+	}
+}
+
+//intScoreSeq converts the members and scores of a zset into integers and floats, skipping anything that can't be converted
+func intScoreSeq(seq iter.Seq2[string, float64]) iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for member, score := range seq {
+			if i, err := atoi(member); err == nil && !yield(i, score) {
+				return
+			}
+		}
+	}
+}
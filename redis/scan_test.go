@@ -0,0 +1,174 @@
+package redis
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanKeys(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_Scan[1]:")
+	inner := p.Prefix("Inner:")
+	a := p.String("A")
+	b := p.List("B")
+	c := inner.String("C")
+	other := r.String("Test_Scan[2]:D")
+	<-a.Set("A")
+	<-b.LeftPush("B")
+	<-c.Set("C")
+	<-other.Set("D")
+
+	found := make([]string, 0, 3)
+	keys, closer := p.Scan(ScanOptions{Count: 1})
+	for key := range keys {
+		found = append(found, key)
+	}
+	closer.Close()
+	sort.Strings(found)
+	if len(found) != 3 || found[0] != "A" || found[1] != "B" || found[2] != "Inner:C" {
+		t.Error("Should have found [A B Inner:C], not", found)
+	}
+
+	found = found[:0]
+	for key := range inner.AllKeys(ScanOptions{}) {
+		found = append(found, key)
+	}
+	if len(found) != 1 || found[0] != "C" {
+		t.Error("Should have found [C], not", found)
+	}
+
+	found = found[:0]
+	for key := range p.AllKeys(ScanOptions{Type: "list"}) {
+		found = append(found, key)
+	}
+	if len(found) != 1 || found[0] != "B" {
+		t.Error("Should have only found the list, not", found)
+	}
+
+	count := 0
+	for key := range r.AllKeys(ScanOptions{Match: "Test_Scan*"}) {
+		if key != "Test_Scan[1]:A" && key != "Test_Scan[1]:B" && key != "Test_Scan[1]:Inner:C" && key != "Test_Scan[2]:D" {
+			t.Error("Should not have found", key)
+		}
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Error("Should have been able to stop after 2 keys")
+	}
+
+	//stopping a channel early has to let go of the scan, instead of leaving it waiting to send the rest
+	keys, closer = r.Scan(ScanOptions{Match: "Test_Scan*", Count: 1})
+	<-keys
+	closer.Close()
+	timeout := time.After(time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-keys:
+		case <-timeout:
+			t.Fatal("The channel should have been closed after signalling that we're done")
+		}
+	}
+
+	<-a.Delete()
+	<-b.Delete()
+	<-c.Delete()
+	<-other.Delete()
+}
+
+func TestScanCollections(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Set("Test_Scan_Set")
+	is := r.IntSet("Test_Scan_IntSet")
+	h := r.Hash("Test_Scan_Hash")
+	ss := r.SortedSet("Test_Scan_SortedSet")
+	sis := r.SortedIntSet("Test_Scan_SortedIntSet")
+	<-s.Delete()
+	<-is.Delete()
+	<-h.Delete()
+	<-ss.Delete()
+	<-sis.Delete()
+
+	for i := 0; i < 100; i++ {
+		s.Add("member" + itoa(i))
+		is.Add(i)
+		h.String("field" + itoa(i)).Set(itoa(i))
+		ss.Add("member"+itoa(i), float64(i))
+		<-sis.Add(i, float64(i))
+	}
+
+	count := 0
+	members, closer := s.Scan(ScanOptions{Count: 10})
+	for range members {
+		count++
+	}
+	closer.Close()
+	if count != 100 {
+		t.Error("Should have scanned 100 members of the set, not", count)
+	}
+
+	count = 0
+	for member := range s.All(ScanOptions{Match: "member1*"}) {
+		if !strings.HasPrefix(member, "member1") {
+			t.Error("Should not have matched", member)
+		}
+		count++
+	}
+	if count != 11 {
+		t.Error("Should have matched 11 members, not", count)
+	}
+
+	sum := 0
+	ints, closer := is.Scan(ScanOptions{})
+	for i := range ints {
+		sum += i
+	}
+	closer.Close()
+	if sum != 4950 {
+		t.Error("Integers should add up to 4950, not", sum)
+	}
+
+	count = 0
+	for field, value := range h.All(ScanOptions{}) {
+		if field != "field"+value {
+			t.Error("Field", field, "should not have", value)
+		}
+		count++
+	}
+	if count != 100 {
+		t.Error("Should have scanned 100 fields, not", count)
+	}
+	fields, closer := h.Scan(ScanOptions{Match: "field42", Type: "string"})
+	for entry := range fields {
+		if entry.Field != "field42" || entry.Value != "42" {
+			t.Error("Should only have found field42, not", entry)
+		}
+	}
+	closer.Close()
+
+	for member, score := range ss.All(ScanOptions{}) {
+		if member != "member"+itoa(int(score)) {
+			t.Error("Member", member, "should not have a score of", score)
+		}
+	}
+	count = 0
+	scored, closer := sis.Scan(ScanOptions{})
+	for entry := range scored {
+		if float64(entry.Member) != entry.Score {
+			t.Error("Member", entry.Member, "should not have a score of", entry.Score)
+		}
+		count++
+	}
+	closer.Close()
+	if count != 100 {
+		t.Error("Should have scanned 100 members of the zset, not", count)
+	}
+}
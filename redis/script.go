@@ -0,0 +1,222 @@
+package redis
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+//Script is a Lua script that gets run within redis.
+//Scripts called directly through a Client are sent by their SHA1 digest (EVALSHA) to save on network costs, and the source only gets sent if redis doesn't have it cached yet.
+//That caching doesn't apply within a Pipeline or Transaction: there the full source is sent with every call (EVAL), whether or not redis has it cached,
+//since a command can't be resent from there once redis says it doesn't have the script
+//(and loading the script first would add a command to the pipe that the caller didn't issue).
+//See http://redis.io/commands/eval for more information on redis scripting
+type Script struct {
+	source   string
+	sha      string
+	executor SafeExecutor
+}
+
+func newScript(client *Client, source string) Script {
+	sum := sha1.Sum([]byte(source))
+	return Script{
+		source:   source,
+		sha:      hex.EncodeToString(sum[:]),
+		executor: client,
+	}
+}
+
+//SHA returns the SHA1 digest that redis uses to identify this script
+func (this Script) SHA() string {
+	return this.sha
+}
+
+//SCRIPT LOAD command -
+//Load makes sure redis has this script cached;
+//returns the SHA1 digest of the script
+func (this Script) Load() <-chan string {
+	return StringCommand(this.executor, "SCRIPT", "LOAD", this.source)
+}
+
+//SCRIPT EXISTS command -
+//Exists returns whether or not redis currently has this script cached
+func (this Script) Exists() <-chan bool {
+	return boolChannel(SliceCommand(this.executor, "SCRIPT", "EXISTS", this.sha), 0)
+}
+
+//Use allows you to run this script on a different executor.
+//Within a Pipeline or Transaction, every call sends the full source of the script (see Script)
+func (this Script) Use(e SafeExecutor) Script {
+	this.executor = e
+	return this
+}
+
+//With sets up a call of this script, and uses the supplied objects as the KEYS the script operates on
+func (this Script) With(keys ...Keyed) *ScriptCall {
+	call := &ScriptCall{
+		script: this,
+		keys:   keyNames(keys),
+	}
+	call.callResult = callResult{call.execute}
+	return call
+}
+
+//Args sets up a call of this script without any KEYS, but with the supplied ARGV
+func (this Script) Args(args ...string) *ScriptCall {
+	return this.With().Args(args...)
+}
+
+func callArguments(op, name string, keys, args []string) []string {
+	result := make([]string, 0, 3+len(keys)+len(args))
+	result = append(result, op, name, itoa(len(keys)))
+	result = append(result, keys...)
+	return append(result, args...)
+}
+
+func keyNames(keys []Keyed) []string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.keyName()
+	}
+	return names
+}
+
+//ScriptCall keeps track of the KEYS and ARGV that a script is being called with.
+//Use any of its Run or Get functions to run the script (with EVALSHA) and decode the result
+type ScriptCall struct {
+	callResult
+	script Script
+	keys   []string
+	args   []string
+}
+
+//Args adds to the arguments (ARGV) that the script will be called with
+func (this *ScriptCall) Args(args ...string) *ScriptCall {
+	this.args = append(this.args, args...)
+	return this
+}
+
+//IntArgs adds integers to the arguments (ARGV) that the script will be called with
+func (this *ScriptCall) IntArgs(args ...int) *ScriptCall {
+	return this.Args(intsToStrings(args)...)
+}
+
+//FloatArgs adds floating point numbers to the arguments (ARGV) that the script will be called with
+func (this *ScriptCall) FloatArgs(args ...float64) *ScriptCall {
+	return this.Args(floatsToStrings(args)...)
+}
+
+func (this *ScriptCall) arguments(op, script string) []string {
+	return callArguments(op, script, this.keys, this.args)
+}
+
+func (this *ScriptCall) execute(inner command) {
+	if _, queued := this.script.executor.(*pipe); queued {
+		//redis might have dropped the script (after SCRIPT FLUSH, a restart, or a failover) by the time the pipe gets sent,
+		//and there's no way to fall back to EVAL from within a pipe
+		this.script.executor.Execute(scriptCommand{
+			command: inner,
+			args:    this.arguments("EVAL", this.script.source),
+		})
+		return
+	}
+	this.script.executor.Execute(scriptCommand{
+		command:  inner,
+		args:     this.arguments("EVALSHA", this.script.sha),
+		fallback: this.arguments("EVAL", this.script.source),
+	})
+}
+
+//callResult decodes whatever a call of server-side code (a Script or a Function) sends back
+type callResult struct {
+	run func(command)
+}
+
+//Run runs the code, ignoring whatever it returns
+func (this callResult) Run() <-chan nothing {
+	c := make(chan nothing, 1)
+	this.run(nilCommand{nil, c})
+	return c
+}
+
+//GetBool runs the code and returns its result as a boolean
+func (this callResult) GetBool() <-chan bool {
+	c := make(chan bool, 1)
+	this.run(boolCommand{nil, c})
+	return c
+}
+
+//GetInt runs the code and returns its result as an integer
+func (this callResult) GetInt() <-chan int {
+	c := make(chan int, 1)
+	this.run(intCommand{nil, c})
+	return c
+}
+
+//GetFloat runs the code and returns its result as a floating point number
+//(Lua numbers get truncated to integers by redis, so the code should return the number as a string)
+func (this callResult) GetFloat() <-chan float64 {
+	c := make(chan float64, 1)
+	this.run(floatCommand{nil, c})
+	return c
+}
+
+//GetString runs the code and returns its result as a string
+func (this callResult) GetString() <-chan string {
+	c := make(chan string, 1)
+	this.run(stringCommand{nil, c})
+	return c
+}
+
+//GetSlice runs the code and returns its result as a slice of strings
+func (this callResult) GetSlice() <-chan []string {
+	c := make(chan []string, 1)
+	this.run(sliceCommand{nil, c})
+	return c
+}
+
+//GetInts runs the code and returns its result as a slice of integers
+func (this callResult) GetInts() <-chan []int {
+	return intsChannel(this.GetSlice())
+}
+
+//GetFloats runs the code and returns its result as a slice of floating point numbers
+func (this callResult) GetFloats() <-chan []float64 {
+	return floatsChannel(this.GetSlice())
+}
+
+//GetMaybeSlice runs the code and returns its result as a slice of strings, with nil wherever the code returned false (or nil)
+func (this callResult) GetMaybeSlice() <-chan []*string {
+	c := make(chan []*string, 1)
+	this.run(maybeSliceCommand{nil, c})
+	return c
+}
+
+//GetMap runs the code and returns its result (a flat list of alternating keys and values) as a map
+func (this callResult) GetMap() <-chan map[string]string {
+	c := make(chan map[string]string, 1)
+	this.run(mapCommand{nil, c})
+	return c
+}
+
+//scriptCommand runs server-side code (a script or a function) and hands the result off to another command for decoding.
+//If a fallback is supplied and redis doesn't have the script cached, the fallback (which includes the source) gets sent instead
+type scriptCommand struct {
+	command
+	args     []string
+	fallback []string
+}
+
+func (this scriptCommand) arguments() []string {
+	return this.args
+}
+
+func (this scriptCommand) retry(err error) command {
+	if this.fallback == nil || !strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		return nil
+	}
+	//EVAL caches the script, so the digest can be used again next time
+	this.args, this.fallback = this.fallback, nil
+	return this
+}
@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"testing"
+)
+
+func TestScripts(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	p := r.Prefix("Test_Script:")
+	s := p.String("String")
+	l := p.List("List")
+	<-s.Delete()
+	<-l.Delete()
+
+	<-NilCommand(r, "SCRIPT", "FLUSH")
+
+	setAndPush := r.Script(`
+		redis.call("SET", KEYS[1], ARGV[1])
+		return redis.call("RPUSH", KEYS[2], ARGV[1], ARGV[2])
+	`)
+
+	if <-setAndPush.Exists() {
+		t.Error("Script should not be cached yet")
+	}
+
+	if res := <-setAndPush.With(s, l).Args("A", "B").GetInt(); res != 2 {
+		t.Error("Script should have pushed 2 items, not", res)
+	}
+	if !<-setAndPush.Exists() {
+		t.Error("Script should be cached now")
+	}
+	if res := <-r.String("Test_Script:String").Get(); res != "A" {
+		t.Error("Script should have used the prefixed key, got", res)
+	}
+	if res := <-l.GetFromRange(0, -1); len(res) != 2 || res[0] != "A" || res[1] != "B" {
+		t.Error("List should be [A B], not", res)
+	}
+
+	get := r.Script(`return redis.call("LRANGE", KEYS[1], 0, -1)`)
+	if res := <-get.With(l).GetSlice(); len(res) != 2 || res[0] != "A" || res[1] != "B" {
+		t.Error("Script should return [A B], not", res)
+	}
+
+	sum := r.Script(`return tonumber(ARGV[1]) + tonumber(ARGV[2])`)
+	<-NilCommand(r, "SCRIPT", "FLUSH")
+
+	var inPipe, inTransaction <-chan int
+	result := r.Pipeline(func(e SafeExecutor) {
+		inPipe = sum.Use(e).Args().IntArgs(1, 2).GetInt()
+	})
+	if !result.Succeeded() {
+		t.Error("Pipeline should have succeeded, errors:", result.Errors)
+	}
+	if res := <-inPipe; res != 3 {
+		t.Error("Script in pipeline should return 3, not", res)
+	}
+
+	result = r.Transaction(func(e SafeExecutor) {
+		inTransaction = sum.Use(e).Args("3", "4").GetInt()
+	})
+	if !result.Succeeded() {
+		t.Error("Transaction should have succeeded, errors:", result.Errors)
+	}
+	if res := <-inTransaction; res != 7 {
+		t.Error("Script in transaction should return 7, not", res)
+	}
+}
+
+func TestScriptsAfterFlush(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	sum := r.Script(`return tonumber(ARGV[1]) + tonumber(ARGV[2])`)
+	<-sum.Load()
+	if res := <-sum.Args("1", "2").GetInt(); res != 3 {
+		t.Error("Script should return 3, not", res)
+	}
+
+	//redis forgets the script, just like it would after a restart or a failover
+	for i := 0; i < 2; i++ {
+		<-NilCommand(r, "SCRIPT", "FLUSH")
+
+		var inTransaction <-chan int
+		result := r.Transaction(func(e SafeExecutor) {
+			inTransaction = sum.Use(e).Args("3", itoa(i)).GetInt()
+		})
+		if !result.Succeeded() {
+			t.Error("Transaction should have succeeded after the scripts were flushed, errors:", result.Errors)
+		}
+		if res := <-inTransaction; res != 3+i {
+			t.Error("Script in transaction should return", 3+i, "not", res)
+		}
+	}
+
+	<-NilCommand(r, "SCRIPT", "FLUSH")
+	if res := <-sum.Args("5", "6").GetInt(); res != 11 {
+		t.Error("Script should be sent again once redis has forgotten it, got", res)
+	}
+}
@@ -0,0 +1,283 @@
+package redis
+
+import (
+	"strings"
+	"time"
+)
+
+//Server encapsulates the commands that administer the redis server itself.
+//See http://redis.io/commands#server for more information on these types of commands
+type Server struct {
+	client SafeExecutor
+}
+
+func newServer(client SafeExecutor) Server {
+	return Server{
+		client: client,
+	}
+}
+
+//Info holds what INFO returns, organized by section (e.g. "server", "clients", "memory", "keyspace").
+//Section names are all lowercase
+type Info map[string]InfoSection
+
+//InfoSection holds the fields of a single section of INFO
+type InfoSection map[string]string
+
+//Int returns a field of the section as an integer (0 if it isn't there or isn't an integer)
+func (this InfoSection) Int(field string) int {
+	i, _ := atoi(this[field])
+	return i
+}
+
+//Float returns a field of the section as a floating point number (0 if it isn't there or isn't a number)
+func (this InfoSection) Float(field string) float64 {
+	f, _ := atof(this[field])
+	return f
+}
+
+//Values splits up a field made up of several comma separated values (e.g. "db0" within the keyspace section, which looks like "keys=1,expires=0,avg_ttl=0")
+func (this InfoSection) Values(field string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(this[field], ",") {
+		if name, value, found := strings.Cut(pair, "="); found {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+func parseInfo(text string) Info {
+	info := make(Info)
+	section := InfoSection(nil)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			section = make(InfoSection)
+			info[strings.ToLower(strings.TrimSpace(line[1:]))] = section
+			continue
+		}
+		if section == nil {
+			section = make(InfoSection)
+			info[""] = section
+		}
+		if name, value, found := strings.Cut(line, ":"); found {
+			section[name] = value
+		}
+	}
+	return info
+}
+
+//INFO command -
+//Info returns information about the server; if no sections are specified, the default sections are returned
+func (this Server) Info(sections ...string) <-chan Info {
+	out := make(chan Info, 1)
+	in := StringCommand(this.client, append([]string{"INFO"}, sections...)...)
+	go func() {
+		defer close(out)
+		if text, ok := <-in; ok {
+			out <- parseInfo(text)
+		}
+	}()
+	return out
+}
+
+//CONFIG GET command -
+//Config returns every configuration parameter that fits the pattern, along with its value
+func (this Server) Config(pattern string) <-chan map[string]string {
+	return MapCommand(this.client, "CONFIG", "GET", pattern)
+}
+
+//CONFIG SET command -
+//SetConfig changes a configuration parameter while the server is running
+func (this Server) SetConfig(parameter, value string) <-chan nothing {
+	return NilCommand(this.client, "CONFIG", "SET", parameter, value)
+}
+
+//CONFIG REWRITE command -
+//RewriteConfig writes the configuration the server is currently using back to its config file
+func (this Server) RewriteConfig() <-chan nothing {
+	return NilCommand(this.client, "CONFIG", "REWRITE")
+}
+
+//CONFIG RESETSTAT command -
+//ResetStats resets the statistics reported by INFO
+func (this Server) ResetStats() <-chan nothing {
+	return NilCommand(this.client, "CONFIG", "RESETSTAT")
+}
+
+//DBSIZE command -
+//DatabaseSize returns the number of keys in the current database
+func (this Server) DatabaseSize() <-chan int {
+	return IntCommand(this.client, "DBSIZE")
+}
+
+//FLUSHDB command -
+//FlushDatabase deletes every key in the current database
+func (this Server) FlushDatabase() <-chan nothing {
+	return NilCommand(this.client, "FLUSHDB", "SYNC")
+}
+
+//FLUSHDB ASYNC command -
+//FlushDatabaseAsync deletes every key in the current database, letting redis free up the memory in the background
+func (this Server) FlushDatabaseAsync() <-chan nothing {
+	return NilCommand(this.client, "FLUSHDB", "ASYNC")
+}
+
+//FLUSHALL command -
+//FlushAll deletes every key in every database
+func (this Server) FlushAll() <-chan nothing {
+	return NilCommand(this.client, "FLUSHALL", "SYNC")
+}
+
+//FLUSHALL ASYNC command -
+//FlushAllAsync deletes every key in every database, letting redis free up the memory in the background
+func (this Server) FlushAllAsync() <-chan nothing {
+	return NilCommand(this.client, "FLUSHALL", "ASYNC")
+}
+
+//SAVE command -
+//Save writes a snapshot of the data to disk, blocking every other client until it is done
+func (this Server) Save() <-chan nothing {
+	return NilCommand(this.client, "SAVE")
+}
+
+//BGSAVE command -
+//BackgroundSave starts writing a snapshot of the data to disk in the background
+func (this Server) BackgroundSave() <-chan nothing {
+	return NilCommand(this.client, "BGSAVE")
+}
+
+//BGREWRITEAOF command -
+//BackgroundRewriteAOF starts rewriting the append-only file in the background
+func (this Server) BackgroundRewriteAOF() <-chan nothing {
+	return NilCommand(this.client, "BGREWRITEAOF")
+}
+
+//LASTSAVE command -
+//LastSave returns the last time a snapshot was successfully written to disk
+func (this Server) LastSave() <-chan time.Time {
+	out := make(chan time.Time, 1)
+	in := IntCommand(this.client, "LASTSAVE")
+	go func() {
+		defer close(out)
+		if seconds, ok := <-in; ok {
+			out <- time.Unix(int64(seconds), 0)
+		}
+	}()
+	return out
+}
+
+//TIME command -
+//Time returns the current time according to the server
+func (this Server) Time() <-chan time.Time {
+	out := make(chan time.Time, 1)
+	in := SliceCommand(this.client, "TIME")
+	go func() {
+		defer close(out)
+		if parts, ok := <-in; ok && len(parts) == 2 {
+			seconds, err := atoi(parts[0])
+			microseconds, err2 := atoi(parts[1])
+			if err == nil && err2 == nil {
+				out <- time.Unix(int64(seconds), 0).Add(time.Duration(microseconds) * time.Microsecond)
+			}
+		}
+	}()
+	return out
+}
+
+//Role describes the part a server plays in replication, as returned by ROLE
+type Role struct {
+	//Role is one of "master", "slave" or "sentinel"
+	Role string
+
+	//ReplicationOffset is the offset of the master's replication stream, or how much of it a replica has received
+	ReplicationOffset int
+
+	//Replicas are the replicas connected to a master
+	Replicas []ReplicaDetails
+
+	//MasterHost and MasterPort are the address of the master a replica is following
+	MasterHost string
+	MasterPort int
+
+	//State is the state of a replica's connection to its master (connect, connecting, sync or connected)
+	State string
+
+	//MasterNames are the masters being monitored by a sentinel
+	MasterNames []string
+}
+
+//ReplicaDetails describes a single replica that is connected to a master
+type ReplicaDetails struct {
+	Host              string
+	Port              int
+	ReplicationOffset int
+}
+
+func parseRole(r *response) Role {
+	values := r.values()
+	role := Role{Role: values[0]}
+	switch role.Role {
+	case "master":
+		if len(values) > 2 {
+			role.ReplicationOffset, _ = atoi(values[1])
+			for _, sub := range r.subresponses[2].subresponses {
+				replica := sub.values()
+				if len(replica) == 3 {
+					details := ReplicaDetails{Host: replica[0]}
+					details.Port, _ = atoi(replica[1])
+					details.ReplicationOffset, _ = atoi(replica[2])
+					role.Replicas = append(role.Replicas, details)
+				}
+			}
+		}
+	case "slave":
+		if len(values) > 4 {
+			role.MasterHost = values[1]
+			role.MasterPort, _ = atoi(values[2])
+			role.State = values[3]
+			role.ReplicationOffset, _ = atoi(values[4])
+		}
+	case "sentinel":
+		if len(values) > 1 {
+			role.MasterNames = r.subresponses[1].values()
+		}
+	}
+	return role
+}
+
+//ROLE command -
+//Role returns the part the server plays in replication
+func (this Server) Role() <-chan Role {
+	out := make(chan Role, 1)
+	in := responseChannel(this.client, "ROLE")
+	go func() {
+		defer close(out)
+		if r, ok := <-in; ok && len(r.subresponses) > 0 {
+			out <- parseRole(r)
+		}
+	}()
+	return out
+}
+
+//REPLICAOF command -
+//ReplicaOf turns the server into a replica of another server
+func (this Server) ReplicaOf(host string, port int) <-chan nothing {
+	return NilCommand(this.client, "REPLICAOF", host, itoa(port))
+}
+
+//REPLICAOF NO ONE command -
+//StopReplicating turns a replica back into a master, keeping the data it has already received
+func (this Server) StopReplicating() <-chan nothing {
+	return NilCommand(this.client, "REPLICAOF", "NO", "ONE")
+}
+
+//Use allows you to use these commands on a different executor
+func (this Server) Use(e SafeExecutor) Server {
+	this.client = e
+	return this
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Server()
+
+	info := <-s.Info()
+	if info["server"]["redis_version"] == "" {
+		t.Error("Should have gotten the version of redis")
+	}
+	if info["server"].Int("tcp_port") != 6379 {
+		t.Error("Should be running on port 6379, not", info["server"]["tcp_port"])
+	}
+	if res := <-s.Info("memory"); len(res) != 1 || res["memory"].Int("used_memory") <= 0 {
+		t.Error("Should have only gotten the memory section, not", res)
+	}
+
+	str := r.String("Test_Server_String")
+	<-str.Set("A")
+	if res := <-s.DatabaseSize(); res < 1 {
+		t.Error("Database should have at least one key, not", res)
+	}
+	if res := (<-s.Info("keyspace"))["keyspace"].Values("db0"); res["keys"] == "" {
+		t.Error("Keyspace should describe db0, not", res)
+	}
+
+	original := (<-s.Config("maxmemory-policy"))["maxmemory-policy"]
+	<-s.SetConfig("maxmemory-policy", "allkeys-lru")
+	if res := (<-s.Config("maxmemory-policy"))["maxmemory-policy"]; res != "allkeys-lru" {
+		t.Error("Policy should be allkeys-lru, not", res)
+	}
+	<-s.SetConfig("maxmemory-policy", original)
+
+	<-s.ResetStats()
+	if res := (<-s.Info("stats"))["stats"].Int("total_commands_processed"); res > 2 {
+		t.Error("Stats should have been reset, instead", res, "commands have been processed")
+	}
+
+	if res := <-s.Time(); res.Sub(time.Now()) > time.Minute || time.Now().Sub(res) > time.Minute {
+		t.Error("Server time should be about now, not", res)
+	}
+	if res, ok := <-s.LastSave(); !ok || res.After(time.Now().Add(time.Minute)) {
+		t.Error("Last save should have been in the past, not", res)
+	}
+
+	if res := <-s.Role(); res.Role != "master" {
+		t.Error("Server should be a master, not", res.Role)
+	}
+
+	<-str.Delete()
+}
@@ -1,5 +1,10 @@
 package redis
 
+import (
+	"io"
+	"iter"
+)
+
 //Set is an object that implements a basic Redis Set primitive
 //see http://redis.io/commands#set for more information on redis sets
 type Set struct {
@@ -136,6 +141,19 @@ func (this Set) MoveMemberTo(newSet Set, item string) <-chan bool {
 	return BoolCommand(this, this.args("smove", newSet.key, item)...)
 }
 
+//SSCAN command -
+//Scan sends back every string in the set that fits the options through a channel, a few at a time.
+//Warning - this keeps using network I/O until every string has been sent back, or until you signal that you're done
+func (this Set) Scan(options ScanOptions) (members <-chan string, finishSignaler io.Closer) {
+	return seqChannel(this.All(options))
+}
+
+//SSCAN command -
+//All is like Scan, but gives back an iterator to be used with range instead of a channel
+func (this Set) All(options ScanOptions) iter.Seq[string] {
+	return scanSeq(this, this.args("sscan"), options)
+}
+
 //Use allows you to use this key on a different executor
 func (this Set) Use(e SafeExecutor) Set {
 	this.client = e
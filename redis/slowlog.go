@@ -0,0 +1,205 @@
+package redis
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	slowLogPollSize = 128
+)
+
+//SlowLogEntry is a single command that took longer to run than the server's "slowlog-log-slower-than" setting
+type SlowLogEntry struct {
+	//ID goes up by one with every entry, and is not reset by ResetSlowLog
+	ID int
+
+	//Time is when the command was run, and Duration is how long it took (not counting network I/O)
+	Time     time.Time
+	Duration time.Duration
+
+	//Arguments are the command and its arguments (redis may shorten them if there are too many or they are too long)
+	Arguments []string
+
+	//ClientAddress and ClientName describe the connection that ran the command
+	ClientAddress string
+	ClientName    string
+}
+
+func parseSlowLogEntry(r *response) SlowLogEntry {
+	values := r.values()
+	entry := SlowLogEntry{}
+	if len(values) < 4 {
+		return entry
+	}
+	entry.ID, _ = atoi(values[0])
+	seconds, _ := atoi(values[1])
+	entry.Time = time.Unix(int64(seconds), 0)
+	microseconds, _ := atoi(values[2])
+	entry.Duration = time.Duration(microseconds) * time.Microsecond
+	entry.Arguments = r.subresponses[3].values()
+	if len(values) > 5 {
+		entry.ClientAddress = values[4]
+		entry.ClientName = values[5]
+	}
+	return entry
+}
+
+func slowLogChannel(in <-chan *response) <-chan []SlowLogEntry {
+	out := make(chan []SlowLogEntry, 1)
+	go func() {
+		defer close(out)
+		if r, ok := <-in; ok {
+			entries := make([]SlowLogEntry, 0, len(r.subresponses))
+			for _, sub := range r.subresponses {
+				entries = append(entries, parseSlowLogEntry(sub))
+			}
+			out <- entries
+		}
+	}()
+	return out
+}
+
+//SLOWLOG GET command -
+//SlowLog returns up to "count" of the most recent entries in the slow log, newest first
+//(use -1 to get every entry)
+func (this Server) SlowLog(count int) <-chan []SlowLogEntry {
+	return slowLogChannel(responseChannel(this.client, "SLOWLOG", "GET", itoa(count)))
+}
+
+//SLOWLOG LEN command -
+//SlowLogLength returns the number of entries in the slow log
+func (this Server) SlowLogLength() <-chan int {
+	return IntCommand(this.client, "SLOWLOG", "LEN")
+}
+
+//SLOWLOG RESET command -
+//ResetSlowLog removes every entry from the slow log
+func (this Server) ResetSlowLog() <-chan nothing {
+	return NilCommand(this.client, "SLOWLOG", "RESET")
+}
+
+//WatchSlowLog checks the slow log every so often, and calls the specified function with every entry that has been added since it started watching (oldest first).
+//If more than a hundred or so entries are added between checks, only the most recent ones are sent.
+//It returns a way to signal when you're done watching
+func (this Server) WatchSlowLog(interval time.Duration, action func(SlowLogEntry)) (finishSignaler io.Closer) {
+	closer := make(chan bool, 1)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastID := -1
+		if entries, ok := <-this.SlowLog(1); ok && len(entries) > 0 {
+			lastID = entries[0].ID
+		}
+		for {
+			select {
+			case <-ticker.C:
+				entries, ok := <-this.SlowLog(slowLogPollSize)
+				if !ok {
+					continue
+				}
+				for i := len(entries) - 1; i >= 0; i-- {
+					if entries[i].ID > lastID {
+						lastID = entries[i].ID
+						action(entries[i])
+					}
+				}
+			case <-closer:
+				return
+			}
+		}
+	}()
+	subsc := (subscription)(closer)
+	return &subsc
+}
+
+//LatencyEvent is the latest spike recorded for a single kind of event (such as "command" or "fork") by the latency monitor
+type LatencyEvent struct {
+	Name string
+
+	//Time is when the latest spike happened, and Latest is how long it took
+	Time   time.Time
+	Latest time.Duration
+
+	//Max is the longest spike recorded for the event
+	Max time.Duration
+}
+
+//LatencySample is a single spike recorded by the latency monitor
+type LatencySample struct {
+	Time    time.Time
+	Latency time.Duration
+}
+
+func milliseconds(value string) time.Duration {
+	ms, _ := atoi(value)
+	return time.Duration(ms) * time.Millisecond
+}
+
+func unixTime(value string) time.Time {
+	seconds, _ := atoi(value)
+	return time.Unix(int64(seconds), 0)
+}
+
+//LATENCY LATEST command -
+//LatestLatency returns the latest spike of every event the latency monitor has recorded.
+//The latency monitor only records events once the server's "latency-monitor-threshold" setting is above 0
+func (this Server) LatestLatency() <-chan []LatencyEvent {
+	out := make(chan []LatencyEvent, 1)
+	in := responseChannel(this.client, "LATENCY", "LATEST")
+	go func() {
+		defer close(out)
+		if r, ok := <-in; ok {
+			events := make([]LatencyEvent, 0, len(r.subresponses))
+			for _, sub := range r.subresponses {
+				if values := sub.values(); len(values) >= 4 {
+					events = append(events, LatencyEvent{
+						Name:   values[0],
+						Time:   unixTime(values[1]),
+						Latest: milliseconds(values[2]),
+						Max:    milliseconds(values[3]),
+					})
+				}
+			}
+			out <- events
+		}
+	}()
+	return out
+}
+
+//LATENCY HISTORY command -
+//LatencyHistory returns the recent spikes of a single event, oldest first
+func (this Server) LatencyHistory(event string) <-chan []LatencySample {
+	out := make(chan []LatencySample, 1)
+	in := responseChannel(this.client, "LATENCY", "HISTORY", event)
+	go func() {
+		defer close(out)
+		if r, ok := <-in; ok {
+			samples := make([]LatencySample, 0, len(r.subresponses))
+			for _, sub := range r.subresponses {
+				if values := sub.values(); len(values) >= 2 {
+					samples = append(samples, LatencySample{
+						Time:    unixTime(values[0]),
+						Latency: milliseconds(values[1]),
+					})
+				}
+			}
+			out <- samples
+		}
+	}()
+	return out
+}
+
+//LATENCY RESET command -
+//ResetLatency clears the recorded spikes of the specified events (or of every event, if none are specified);
+//returns the number of events that were cleared
+func (this Server) ResetLatency(events ...string) <-chan int {
+	return IntCommand(this.client, append([]string{"LATENCY", "RESET"}, events...)...)
+}
+
+//LATENCY DOCTOR command -
+//LatencyDoctor returns a human readable report of the spikes that have been recorded, along with advice on what could be causing them
+func (this Server) LatencyDoctor() <-chan string {
+	return StringCommand(this.client, "LATENCY", "DOCTOR")
+}
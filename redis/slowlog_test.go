@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowLog(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Server()
+
+	original := (<-s.Config("slowlog-log-slower-than"))["slowlog-log-slower-than"]
+	<-s.SetConfig("slowlog-log-slower-than", "0")
+	defer func() {
+		<-s.SetConfig("slowlog-log-slower-than", original)
+	}()
+
+	<-s.ResetSlowLog()
+	str := r.String("Test_SlowLog_String")
+	<-str.Set("A")
+
+	entries := <-s.SlowLog(-1)
+	found := false
+	for _, entry := range entries {
+		if len(entry.Arguments) == 3 && entry.Arguments[1] == "Test_SlowLog_String" {
+			found = true
+			if entry.Time.After(time.Now().Add(time.Minute)) || entry.ClientAddress == "" {
+				t.Error("Slow log entry wasn't parsed properly -", entry)
+			}
+		}
+	}
+	if !found {
+		t.Error("Should have found the SET command in the slow log, not", entries)
+	}
+	if res := <-s.SlowLogLength(); res < 1 {
+		t.Error("Slow log should have at least one entry, not", res)
+	}
+
+	watched := make(chan SlowLogEntry, 16)
+	closer := s.WatchSlowLog(10*time.Millisecond, func(entry SlowLogEntry) {
+		watched <- entry
+	})
+	time.Sleep(50 * time.Millisecond)
+	<-str.Get()
+	timeout := time.After(time.Second)
+	seen := false
+	for !seen {
+		select {
+		case entry := <-watched:
+			seen = len(entry.Arguments) == 2 && entry.Arguments[0] == "GET" && entry.Arguments[1] == "Test_SlowLog_String"
+		case <-timeout:
+			t.Error("Should have seen the GET command while watching the slow log")
+			seen = true
+		}
+	}
+	closer.Close()
+
+	<-str.Delete()
+}
+
+func TestLatency(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Server()
+
+	original := (<-s.Config("latency-monitor-threshold"))["latency-monitor-threshold"]
+	<-s.SetConfig("latency-monitor-threshold", "1")
+	defer func() {
+		<-s.SetConfig("latency-monitor-threshold", original)
+	}()
+
+	<-s.ResetLatency()
+	<-NilCommand(r, "DEBUG", "SLEEP", "0.01")
+
+	events := <-s.LatestLatency()
+	if len(events) != 1 || events[0].Name != "command" || events[0].Latest < 10*time.Millisecond {
+		t.Error("Should have recorded the slow command, not", events)
+	}
+	if res := <-s.LatencyHistory("command"); len(res) != 1 || res[0].Latency < 10*time.Millisecond {
+		t.Error("Should have one sample in the history of the command event, not", res)
+	}
+	if res := <-s.LatencyDoctor(); res == "" {
+		t.Error("Should have gotten a latency report")
+	}
+	if res := <-s.ResetLatency("command"); res != 1 {
+		t.Error("Should have reset the command event, not", res)
+	}
+}
@@ -1,5 +1,10 @@
 package redis
 
+import (
+	"io"
+	"iter"
+)
+
 //TODO: refactor to use SortedSet code
 
 //SortedIntSet is an object which implements the Redis ZSet Primitive assume all inputs are ints (which is useful for indexes)
@@ -104,6 +109,22 @@ func (this SortedIntSet) RemoveIndexedBetween(start, stop int) <-chan int {
 	return IntCommand(this, this.args("zremrangebyrank", itoa(start), itoa(stop))...)
 }
 
+//ZSCAN command -
+//Scan sends back every member of the zset that fits the options (along with its score) through a channel, a few at a time.
+//The members do not come back in order.
+//Warning - this keeps using network I/O until every member has been sent back, or until you signal that you're done
+func (this SortedIntSet) Scan(options ScanOptions) (members <-chan ScoredIntMember, finishSignaler io.Closer) {
+	return seq2Channel(this.All(options), func(member int, score float64) ScoredIntMember {
+		return ScoredIntMember{member, score}
+	})
+}
+
+//ZSCAN command -
+//All is like Scan, but gives back an iterator of members and scores to be used with range instead of a channel
+func (this SortedIntSet) All(options ScanOptions) iter.Seq2[int, float64] {
+	return intScoreSeq(scoreSeq(scanPairSeq(this, this.args("zscan"), options)))
+}
+
 //SortedIntSetRange keeps track of all range arguments being used in a search
 type SortedIntSetRange struct {
 	min, max      string
@@ -1,5 +1,10 @@
 package redis
 
+import (
+	"io"
+	"iter"
+)
+
 type SortedSet struct {
 	SortableKey
 }
@@ -100,6 +105,22 @@ func (this SortedSet) RemoveIndexedBetween(start, stop int) <-chan int {
 	return IntCommand(this, this.args("zremrangebyrank", itoa(start), itoa(stop))...)
 }
 
+//ZSCAN command -
+//Scan sends back every member of the zset that fits the options (along with its score) through a channel, a few at a time.
+//The members do not come back in order.
+//Warning - this keeps using network I/O until every member has been sent back, or until you signal that you're done
+func (this SortedSet) Scan(options ScanOptions) (members <-chan ScoredMember, finishSignaler io.Closer) {
+	return seq2Channel(this.All(options), func(member string, score float64) ScoredMember {
+		return ScoredMember{member, score}
+	})
+}
+
+//ZSCAN command -
+//All is like Scan, but gives back an iterator of members and scores to be used with range instead of a channel
+func (this SortedSet) All(options ScanOptions) iter.Seq2[string, float64] {
+	return scoreSeq(scanPairSeq(this, this.args("zscan"), options))
+}
+
 //SortedSetRange keeps track of all range arguments being used in a search
 type SortedSetRange struct {
 	min, max      string
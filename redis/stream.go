@@ -0,0 +1,395 @@
+package redis
+
+import (
+	"io"
+	"iter"
+	"sort"
+	"time"
+)
+
+//The special IDs that a Stream understands
+const (
+	//StreamStart and StreamEnd are the lowest and highest possible IDs, for reading a whole stream with Range
+	StreamStart = "-"
+	StreamEnd   = "+"
+
+	//StreamNew is the ID of the last entry that was added to a stream before a blocking read began, so that only newer entries are read
+	StreamNew = "$"
+)
+
+//StreamEntry is a single entry of a Stream
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string
+}
+
+//StreamTrim describes how to trim a stream; set either MaxLength or MinID.
+//The zero value doesn't trim anything
+type StreamTrim struct {
+	//MaxLength removes the oldest entries until there are only this many left
+	MaxLength int
+
+	//MinID removes every entry with a lower ID
+	MinID string
+
+	//Approximate lets redis trim a bit less than asked for, which is much more efficient
+	Approximate bool
+
+	//Limit caps how many entries are removed at once when trimming approximately (0 leaves it up to redis)
+	Limit int
+}
+
+func (this StreamTrim) args() []string {
+	var result []string
+	switch {
+	case this.MinID != "":
+		result = []string{"MINID"}
+	case this.MaxLength > 0:
+		result = []string{"MAXLEN"}
+	default:
+		return nil
+	}
+	if this.Approximate {
+		result = append(result, "~")
+	} else {
+		result = append(result, "=")
+	}
+	if this.MinID != "" {
+		result = append(result, this.MinID)
+	} else {
+		result = append(result, itoa(this.MaxLength))
+	}
+	if this.Approximate && this.Limit > 0 {
+		result = append(result, "LIMIT", itoa(this.Limit))
+	}
+	return result
+}
+
+//StreamAddOptions changes how an entry is added to a stream
+type StreamAddOptions struct {
+	//ID is the ID of the new entry; redis generates one if it is left empty
+	ID string
+
+	//NoCreate doesn't add the entry if the stream doesn't already exist
+	NoCreate bool
+
+	//Trim trims the stream after adding the entry
+	Trim StreamTrim
+}
+
+//StreamInfo describes a stream, as reported by XINFO STREAM
+type StreamInfo struct {
+	Length          int
+	Groups          int
+	LastGeneratedID string
+
+	//EntriesAdded counts every entry that has ever been added to the stream, including ones that were deleted since
+	EntriesAdded int
+
+	//FirstEntry and LastEntry are nil if the stream is empty
+	FirstEntry *StreamEntry
+	LastEntry  *StreamEntry
+}
+
+//StreamPosition is where to start reading a stream from, with ReadStreams or BlockUntilReadStreams:
+//only entries with a higher ID than After are read
+type StreamPosition struct {
+	Stream Stream
+	After  string
+}
+
+//A Stream is an append-only log of entries, each with a unique ID and some fields.
+//See http://redis.io/topics/streams-intro for more information on redis streams
+type Stream struct {
+	Key
+}
+
+func newStream(client SafeExecutor, key string) Stream {
+	return Stream{
+		newKey(client, key),
+	}
+}
+
+func parseStreamEntry(r *response) StreamEntry {
+	entry := StreamEntry{Fields: map[string]string{}}
+	if r == nil || len(r.subresponses) < 2 {
+		return entry
+	}
+	entry.ID = r.subresponses[0].value()
+	for field, value := range r.subresponses[1].fields() {
+		entry.Fields[field] = value.value()
+	}
+	return entry
+}
+
+func parseStreamEntries(r *response) []StreamEntry {
+	if r == nil {
+		return []StreamEntry{}
+	}
+	entries := make([]StreamEntry, 0, len(r.subresponses))
+	for _, sub := range r.subresponses {
+		if sub != nil {
+			entries = append(entries, parseStreamEntry(sub))
+		}
+	}
+	return entries
+}
+
+func streamEntriesChannel(in <-chan *response) <-chan []StreamEntry {
+	out := make(chan []StreamEntry, 1)
+	go func() {
+		defer close(out)
+		if r, ok := <-in; ok {
+			out <- parseStreamEntries(r)
+		}
+	}()
+	return out
+}
+
+//streamFieldArgs lists the fields of an entry in alphabetical order, so that every entry added with the same fields looks the same
+func streamFieldArgs(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		result = append(result, name, fields[name])
+	}
+	return result
+}
+
+//IsValid returns whether the underlying redis object can use the commands in this object
+func (this Stream) IsValid() <-chan bool {
+	c := make(chan bool, 1)
+	func() {
+		defer close(c)
+		c <- (<-this.Type() == "stream")
+	}()
+	return c
+}
+
+//XADD command -
+//Add adds an entry to the end of this stream; returns the ID that redis generated for it
+func (this Stream) Add(fields map[string]string) <-chan string {
+	return this.AddWithOptions(fields, StreamAddOptions{})
+}
+
+//XADD command -
+//AddWithOptions adds an entry to the end of this stream; returns the ID of the new entry.
+//If the stream doesn't exist and NoCreate is set, nothing is returned
+func (this Stream) AddWithOptions(fields map[string]string, options StreamAddOptions) <-chan string {
+	args := []string{}
+	if options.NoCreate {
+		args = append(args, "NOMKSTREAM")
+	}
+	args = append(args, options.Trim.args()...)
+	if options.ID != "" {
+		args = append(args, options.ID)
+	} else {
+		args = append(args, "*")
+	}
+	return StringCommand(this, this.args("xadd", append(args, streamFieldArgs(fields)...)...)...)
+}
+
+//XLEN command -
+//Length returns the number of entries in this stream
+func (this Stream) Length() <-chan int {
+	return IntCommand(this, this.args("xlen")...)
+}
+
+//XRANGE command -
+//Range returns the entries with IDs between start and end (inclusive), oldest first.
+//Use StreamStart and StreamEnd for either end of the stream, and prefix an ID with "(" to leave it out.
+//If count is positive, no more than that many entries are returned
+func (this Stream) Range(start, end string, count int) <-chan []StreamEntry {
+	args := []string{start, end}
+	if count > 0 {
+		args = append(args, "COUNT", itoa(count))
+	}
+	return streamEntriesChannel(responseChannel(this, this.args("xrange", args...)...))
+}
+
+//XREVRANGE command -
+//ReverseRange is like Range, but returns the entries newest first (so end comes before start)
+func (this Stream) ReverseRange(end, start string, count int) <-chan []StreamEntry {
+	args := []string{end, start}
+	if count > 0 {
+		args = append(args, "COUNT", itoa(count))
+	}
+	return streamEntriesChannel(responseChannel(this, this.args("xrevrange", args...)...))
+}
+
+//XRANGE command -
+//Entries sends back every entry of this stream, oldest first, reading "batchSize" entries at a time until there are none left, or until you signal that you're done
+func (this Stream) Entries(batchSize int) (entries <-chan StreamEntry, finishSignaler io.Closer) {
+	return seqChannel(this.All(batchSize))
+}
+
+//XRANGE command -
+//All is like Entries, but gives back an iterator to be used with range instead of a channel.
+//
+//It can't be used within a Pipeline or Transaction, since it needs to keep talking to redis as it goes
+func (this Stream) All(batchSize int) iter.Seq[StreamEntry] {
+	return func(yield func(StreamEntry) bool) {
+		start := StreamStart
+		for {
+			entries, ok := <-this.Range(start, StreamEnd, batchSize)
+			if !ok {
+				return
+			}
+			for _, entry := range entries {
+				if !yield(entry) {
+					return
+				}
+			}
+			if batchSize <= 0 || len(entries) < batchSize {
+				return
+			}
+			start = "(" + entries[len(entries)-1].ID
+		}
+	}
+}
+
+//XDEL command -
+//Remove removes the entries with the specified IDs; returns the number of entries that were removed
+func (this Stream) Remove(ids ...string) <-chan int {
+	return IntCommand(this, this.args("xdel", ids...)...)
+}
+
+//XTRIM command -
+//Trim removes the oldest entries from this stream; returns the number of entries that were removed
+func (this Stream) Trim(trim StreamTrim) <-chan int {
+	return IntCommand(this, this.args("xtrim", trim.args()...)...)
+}
+
+//XINFO STREAM command -
+//Info describes this stream
+func (this Stream) Info() <-chan StreamInfo {
+	out := make(chan StreamInfo, 1)
+	in := responseChannel(this, "XINFO", "STREAM", this.key)
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok {
+			return
+		}
+		fields := r.fields()
+		info := StreamInfo{LastGeneratedID: fields["last-generated-id"].value()}
+		info.Length, _ = atoi(fields["length"].value())
+		info.Groups, _ = atoi(fields["groups"].value())
+		info.EntriesAdded, _ = atoi(fields["entries-added"].value())
+		if first := fields["first-entry"]; first != nil {
+			entry := parseStreamEntry(first)
+			info.FirstEntry = &entry
+		}
+		if last := fields["last-entry"]; last != nil {
+			entry := parseStreamEntry(last)
+			info.LastEntry = &entry
+		}
+		out <- info
+	}()
+	return out
+}
+
+//XRANGE command -
+//Read returns the entries with a higher ID than "after", oldest first.
+//If count is positive, no more than that many entries are returned
+func (this Stream) Read(after string, count int) <-chan []StreamEntry {
+	return this.Range("("+after, StreamEnd, count)
+}
+
+//XREAD command -
+//BlockUntilRead is like Read, but if there aren't any entries with a higher ID, waits up to "timeout" for one to be added
+//(or forever if the timeout is 0). Use StreamNew to only read entries that are added after it starts waiting.
+//If nothing is added in time, the channel is closed without a value
+func (this Stream) BlockUntilRead(after string, count int, timeout time.Duration) <-chan []StreamEntry {
+	return this.read(BlockUntilReadStreams(count, timeout, StreamPosition{this, after}))
+}
+
+func (this Stream) read(in <-chan [][]StreamEntry) <-chan []StreamEntry {
+	out := make(chan []StreamEntry, 1)
+	go func() {
+		defer close(out)
+		if entries, ok := <-in; ok {
+			out <- entries[0]
+		}
+	}()
+	return out
+}
+
+//Use allows you to use this key on a different executor
+func (this Stream) Use(e SafeExecutor) Stream {
+	this.client = e
+	return this
+}
+
+//XREAD command -
+//ReadStreams reads from several streams at once, returning the entries of each stream in the same order as the positions.
+//If count is positive, no more than that many entries are returned from each stream.
+//If none of the streams have any entries to read, the channel is closed without a value.
+//Every stream must belong to the same Client (the first stream's executor is used)
+func ReadStreams(count int, positions ...StreamPosition) <-chan [][]StreamEntry {
+	return readStreams(positions, streamReadArgs("XREAD", count, -1, positions))
+}
+
+//XREAD command -
+//BlockUntilReadStreams is like ReadStreams, but if none of the streams have any entries to read,
+//waits up to "timeout" for one to be added (or forever if the timeout is 0).
+//If nothing is added in time, the channel is closed without a value
+func BlockUntilReadStreams(count int, timeout time.Duration, positions ...StreamPosition) <-chan [][]StreamEntry {
+	return readStreams(positions, streamReadArgs("XREAD", count, timeout, positions))
+}
+
+//streamReadArgs puts together the options and STREAMS arguments of XREAD and XREADGROUP; a negative timeout doesn't block
+func streamReadArgs(command string, count int, timeout time.Duration, positions []StreamPosition, options ...string) []string {
+	args := append([]string{command}, options...)
+	if count > 0 {
+		args = append(args, "COUNT", itoa(count))
+	}
+	if timeout >= 0 {
+		args = append(args, "BLOCK", itoa(int(timeout/time.Millisecond)))
+	}
+	args = append(args, "STREAMS")
+	for _, position := range positions {
+		args = append(args, position.Stream.key)
+	}
+	for _, position := range positions {
+		args = append(args, position.After)
+	}
+	return args
+}
+
+//readStreams matches up the streams that XREAD (or XREADGROUP) returns with the positions they were read from.
+//Redis leaves out the streams that didn't have anything to read
+func readStreams(positions []StreamPosition, args []string) <-chan [][]StreamEntry {
+	out := make(chan [][]StreamEntry, 1)
+	if len(positions) == 0 {
+		close(out)
+		return out
+	}
+	in := responseChannel(positions[0].Stream, args...)
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok {
+			return
+		}
+		byKey := make(map[string][]StreamEntry, len(r.subresponses))
+		for _, sub := range r.subresponses {
+			if sub != nil && len(sub.subresponses) == 2 {
+				byKey[sub.subresponses[0].value()] = parseStreamEntries(sub.subresponses[1])
+			}
+		}
+		result := make([][]StreamEntry, len(positions))
+		for i, position := range positions {
+			result[i] = byKey[position.Stream.key]
+			if result[i] == nil {
+				result[i] = []StreamEntry{}
+			}
+		}
+		out <- result
+	}()
+	return out
+}
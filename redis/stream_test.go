@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreams(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Prefix("Test_").Stream("Stream")
+	<-s.Delete()
+
+	if id, ok := <-s.AddWithOptions(map[string]string{"a": "1"}, StreamAddOptions{NoCreate: true}); ok {
+		t.Error("Shouldn't have created the stream, but added", id)
+	}
+
+	for i := 1; i <= 5; i++ {
+		<-s.AddWithOptions(map[string]string{"n": itoa(i)}, StreamAddOptions{ID: "1-" + itoa(i)})
+	}
+	if id := <-s.Add(map[string]string{"n": "6", "extra": "yes"}); id == "" {
+		t.Error("Should have generated an ID")
+	}
+	if valid := <-s.IsValid(); !valid {
+		t.Error("Should be a valid stream")
+	}
+	if length := <-s.Length(); length != 6 {
+		t.Error("Should have 6 entries, not", length)
+	}
+
+	entries := <-s.Range(StreamStart, StreamEnd, 2)
+	if len(entries) != 2 || entries[0].ID != "1-1" || entries[1].Fields["n"] != "2" {
+		t.Error("Range returned the wrong entries:", entries)
+	}
+	entries = <-s.ReverseRange(StreamEnd, StreamStart, 1)
+	if len(entries) != 1 || entries[0].Fields["extra"] != "yes" {
+		t.Error("ReverseRange returned the wrong entries:", entries)
+	}
+	entries = <-s.Read("1-4", 0)
+	if len(entries) != 2 || entries[0].ID != "1-5" {
+		t.Error("Read returned the wrong entries:", entries)
+	}
+
+	count := 0
+	for entry := range s.All(2) {
+		count++
+		if count == 1 && entry.ID != "1-1" {
+			t.Error("All should start with the oldest entry, not", entry)
+		}
+	}
+	if count != 6 {
+		t.Error("All should have gone through 6 entries, not", count)
+	}
+
+	if removed := <-s.Remove("1-1", "1-9"); removed != 1 {
+		t.Error("Should have removed 1 entry, not", removed)
+	}
+	if removed := <-s.Trim(StreamTrim{MaxLength: 3}); removed != 2 {
+		t.Error("Should have trimmed 2 entries, not", removed)
+	}
+	<-s.AddWithOptions(map[string]string{"n": "7"}, StreamAddOptions{Trim: StreamTrim{MaxLength: 3}})
+	if info := <-s.Info(); info.Length != 3 || info.FirstEntry == nil || info.FirstEntry.ID == "1-4" {
+		t.Error("Info should describe 3 entries, after the first one was trimmed:", info)
+	}
+}
+
+func TestReadStreams(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	a, b := r.Stream("Test_Stream_A"), r.Stream("Test_Stream_B")
+	<-a.Delete()
+	<-b.Delete()
+
+	<-a.Add(map[string]string{"from": "a"})
+	read := <-ReadStreams(0, StreamPosition{a, "0"}, StreamPosition{b, "0"})
+	if len(read) != 2 || len(read[0]) != 1 || len(read[1]) != 0 {
+		t.Error("Should have read one entry from the first stream, not", read)
+	}
+
+	blocked := BlockUntilReadStreams(0, 2*time.Second, StreamPosition{a, StreamNew}, StreamPosition{b, StreamNew})
+	time.Sleep(50 * time.Millisecond)
+	b.Add(map[string]string{"from": "b"})
+	read = <-blocked
+	if len(read) != 2 || len(read[0]) != 0 || len(read[1]) != 1 || read[1][0].Fields["from"] != "b" {
+		t.Error("Should have waited for the entry added to the second stream, not", read)
+	}
+
+	if _, ok := <-a.BlockUntilRead(StreamNew, 0, 50*time.Millisecond); ok {
+		t.Error("Nothing should have been read before timing out")
+	}
+}
@@ -0,0 +1,282 @@
+package redis
+
+import (
+	"time"
+)
+
+//StreamNext is the ID that a consumer reads from to get the entries that haven't been delivered to anyone in its group yet
+const StreamNext = ">"
+
+//PendingSummary describes the entries that have been delivered to a consumer group but not yet acknowledged
+type PendingSummary struct {
+	Count int
+
+	//Lowest and Highest are the IDs of the oldest and newest pending entries
+	Lowest  string
+	Highest string
+
+	//Consumers is the number of pending entries each consumer has
+	Consumers map[string]int
+}
+
+//PendingEntry is a single entry that has been delivered to a consumer but not yet acknowledged
+type PendingEntry struct {
+	ID       string
+	Consumer string
+
+	//Idle is how long it has been since the entry was last delivered
+	Idle time.Duration
+
+	//Deliveries is the number of times the entry has been delivered (including being claimed by another consumer)
+	Deliveries int
+}
+
+//PendingOptions narrows down the pending entries returned by Pending
+type PendingOptions struct {
+	//Start and End limit the IDs of the entries (the whole stream if left empty)
+	Start string
+	End   string
+
+	//Count is the most entries to return (10 if left at 0)
+	Count int
+
+	//MinIdle only returns entries that haven't been delivered for at least this long
+	MinIdle time.Duration
+
+	//Consumer only returns the entries delivered to a single consumer
+	Consumer string
+}
+
+func (this PendingOptions) args() []string {
+	result := []string{}
+	if this.MinIdle > 0 {
+		result = append(result, "IDLE", itoa(int(this.MinIdle/time.Millisecond)))
+	}
+	start, end, count := this.Start, this.End, this.Count
+	if start == "" {
+		start = StreamStart
+	}
+	if end == "" {
+		end = StreamEnd
+	}
+	if count <= 0 {
+		count = 10
+	}
+	result = append(result, start, end, itoa(count))
+	if this.Consumer != "" {
+		result = append(result, this.Consumer)
+	}
+	return result
+}
+
+//AutoClaimed is what XAUTOCLAIM sends back
+type AutoClaimed struct {
+	//Next is where to start the next search for entries to claim ("0-0" once the whole stream has been searched)
+	Next string
+
+	//Entries have been claimed by the consumer
+	Entries []StreamEntry
+
+	//Deleted are the IDs of pending entries that were removed from the stream, and so have been acknowledged
+	Deleted []string
+}
+
+//A ConsumerGroup shares out the entries of a Stream between several consumers, keeping track of which entries each of them has acknowledged.
+//See http://redis.io/topics/streams-intro for more information on consumer groups
+type ConsumerGroup struct {
+	stream Stream
+	name   string
+}
+
+//Group creates the definition for a consumer group of this stream.
+//This is a lightweight function - does *not* involve network I/O (use Create to create the group within redis)
+func (this Stream) Group(name string) ConsumerGroup {
+	return ConsumerGroup{
+		stream: this,
+		name:   name,
+	}
+}
+
+//Name returns the name of the consumer group
+func (this ConsumerGroup) Name() string {
+	return this.name
+}
+
+//Stream returns the stream that the consumer group belongs to
+func (this ConsumerGroup) Stream() Stream {
+	return this.stream
+}
+
+func (this ConsumerGroup) args(command string, arguments ...string) []string {
+	return append([]string{command, this.stream.key, this.name}, arguments...)
+}
+
+func (this ConsumerGroup) groupArgs(subcommand string, arguments ...string) []string {
+	return append([]string{"XGROUP", subcommand, this.stream.key, this.name}, arguments...)
+}
+
+//XGROUP CREATE command -
+//Create creates the consumer group (and the stream, if it doesn't exist yet).
+//The group starts off having been delivered every entry up to "startID" - use StreamNew for only the entries added from now on, or "0" for every entry
+func (this ConsumerGroup) Create(startID string) <-chan nothing {
+	return NilCommand(this.stream, this.groupArgs("CREATE", startID, "MKSTREAM")...)
+}
+
+//XGROUP SETID command -
+//SetID changes the last entry that the group has been delivered, so that the next entries read are the ones after it
+func (this ConsumerGroup) SetID(id string) <-chan nothing {
+	return NilCommand(this.stream, this.groupArgs("SETID", id)...)
+}
+
+//XGROUP DESTROY command -
+//Destroy removes the consumer group, along with all of its consumers and pending entries;
+//returns whether or not the group existed
+func (this ConsumerGroup) Destroy() <-chan bool {
+	return BoolCommand(this.stream, this.groupArgs("DESTROY")...)
+}
+
+//XGROUP CREATECONSUMER command -
+//CreateConsumer adds a consumer to the group (which otherwise happens the first time it reads);
+//returns whether or not the consumer was created
+func (this ConsumerGroup) CreateConsumer(consumer string) <-chan bool {
+	return BoolCommand(this.stream, this.groupArgs("CREATECONSUMER", consumer)...)
+}
+
+//XGROUP DELCONSUMER command -
+//DeleteConsumer removes a consumer from the group; returns the number of pending entries it had, which are no longer pending for anyone
+func (this ConsumerGroup) DeleteConsumer(consumer string) <-chan int {
+	return IntCommand(this.stream, this.groupArgs("DELCONSUMER", consumer)...)
+}
+
+//XREADGROUP command -
+//Read delivers up to "count" entries that haven't been delivered to anyone in the group yet to the consumer (every one of them if count isn't positive).
+//The entries are pending until they're acknowledged.
+//If there aren't any entries to deliver, the channel is closed without a value
+func (this ConsumerGroup) Read(consumer string, count int) <-chan []StreamEntry {
+	return this.read(consumer, StreamNext, count, -1)
+}
+
+//XREADGROUP command -
+//BlockUntilRead is like Read, but if there aren't any entries to deliver, waits up to "timeout" for one to be added (or forever if the timeout is 0)
+func (this ConsumerGroup) BlockUntilRead(consumer string, count int, timeout time.Duration) <-chan []StreamEntry {
+	return this.read(consumer, StreamNext, count, timeout)
+}
+
+//XREADGROUP command -
+//ReadPending returns the entries that have been delivered to the consumer but not yet acknowledged, with a higher ID than "after" (use "0" for all of them)
+func (this ConsumerGroup) ReadPending(consumer, after string, count int) <-chan []StreamEntry {
+	return this.read(consumer, after, count, -1)
+}
+
+func (this ConsumerGroup) read(consumer, after string, count int, timeout time.Duration) <-chan []StreamEntry {
+	positions := []StreamPosition{{this.stream, after}}
+	return this.stream.read(readStreams(positions, streamReadArgs("XREADGROUP", count, timeout, positions, "GROUP", this.name, consumer)))
+}
+
+//XACK command -
+//Acknowledge marks the entries as having been dealt with, so that they are no longer pending;
+//returns the number of entries that were pending
+func (this ConsumerGroup) Acknowledge(ids ...string) <-chan int {
+	return IntCommand(this.stream, this.args("XACK", ids...)...)
+}
+
+//XPENDING command -
+//PendingSummary describes the entries that the group has been delivered, but hasn't acknowledged yet
+func (this ConsumerGroup) PendingSummary() <-chan PendingSummary {
+	out := make(chan PendingSummary, 1)
+	in := responseChannel(this.stream, this.args("XPENDING")...)
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok {
+			return
+		}
+		values := r.values()
+		summary := PendingSummary{Consumers: map[string]int{}}
+		if len(values) < 4 {
+			out <- summary
+			return
+		}
+		summary.Count, _ = atoi(values[0])
+		summary.Lowest = values[1]
+		summary.Highest = values[2]
+		if consumers := r.subresponses[3]; consumers != nil {
+			for _, consumer := range consumers.subresponses {
+				if pair := consumer.values(); len(pair) == 2 {
+					summary.Consumers[pair[0]], _ = atoi(pair[1])
+				}
+			}
+		}
+		out <- summary
+	}()
+	return out
+}
+
+//XPENDING command -
+//Pending lists the entries that the group has been delivered but hasn't acknowledged yet, oldest first
+func (this ConsumerGroup) Pending(options PendingOptions) <-chan []PendingEntry {
+	out := make(chan []PendingEntry, 1)
+	in := responseChannel(this.stream, this.args("XPENDING", options.args()...)...)
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok {
+			return
+		}
+		entries := make([]PendingEntry, 0, len(r.subresponses))
+		for _, sub := range r.subresponses {
+			values := sub.values()
+			if len(values) < 4 {
+				continue
+			}
+			entry := PendingEntry{ID: values[0], Consumer: values[1], Idle: milliseconds(values[2])}
+			entry.Deliveries, _ = atoi(values[3])
+			entries = append(entries, entry)
+		}
+		out <- entries
+	}()
+	return out
+}
+
+//XCLAIM command -
+//Claim gives the consumer the pending entries (out of the ones specified) that haven't been delivered for at least "minIdle",
+//as if they had just been delivered to it; returns the entries that were claimed
+func (this ConsumerGroup) Claim(consumer string, minIdle time.Duration, ids ...string) <-chan []StreamEntry {
+	args := append([]string{consumer, itoa(int(minIdle / time.Millisecond))}, ids...)
+	return streamEntriesChannel(responseChannel(this.stream, this.args("XCLAIM", args...)...))
+}
+
+//XAUTOCLAIM command -
+//AutoClaim searches the pending entries from "start" onwards (use "0-0" for the beginning),
+//giving the consumer up to "count" of the ones that haven't been delivered for at least "minIdle"
+func (this ConsumerGroup) AutoClaim(consumer string, minIdle time.Duration, start string, count int) <-chan AutoClaimed {
+	out := make(chan AutoClaimed, 1)
+	args := []string{consumer, itoa(int(minIdle / time.Millisecond)), start}
+	if count > 0 {
+		args = append(args, "COUNT", itoa(count))
+	}
+	in := responseChannel(this.stream, this.args("XAUTOCLAIM", args...)...)
+	go func() {
+		defer close(out)
+		r, ok := <-in
+		if !ok || len(r.subresponses) < 2 {
+			return
+		}
+		claimed := AutoClaimed{
+			Next:    r.subresponses[0].value(),
+			Entries: parseStreamEntries(r.subresponses[1]),
+			Deleted: []string{},
+		}
+		if len(r.subresponses) > 2 {
+			claimed.Deleted = r.subresponses[2].values()
+		}
+		out <- claimed
+	}()
+	return out
+}
+
+//Use allows you to use this consumer group on a different executor
+func (this ConsumerGroup) Use(e SafeExecutor) ConsumerGroup {
+	this.stream = this.stream.Use(e)
+	return this
+}
@@ -0,0 +1,190 @@
+package redis
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsumerGroups(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Stream("Test_Group_Stream")
+	<-s.Delete()
+	group := s.Group("Test_Group")
+	<-group.Create("0")
+
+	for i := 1; i <= 3; i++ {
+		<-s.Add(map[string]string{"n": itoa(i)})
+	}
+	if created := <-group.CreateConsumer("c2"); !created {
+		t.Error("Should have created consumer c2")
+	}
+
+	entries := <-group.Read("c1", 2)
+	if len(entries) != 2 || entries[0].Fields["n"] != "1" {
+		t.Error("c1 should have been delivered the first 2 entries, not", entries)
+	}
+	entries = <-group.Read("c2", 0)
+	if len(entries) != 1 || entries[0].Fields["n"] != "3" {
+		t.Error("c2 should have been delivered the last entry, not", entries)
+	}
+
+	summary := <-group.PendingSummary()
+	if summary.Count != 3 || summary.Consumers["c1"] != 2 || summary.Consumers["c2"] != 1 {
+		t.Error("Should have 3 pending entries, not", summary)
+	}
+	if acked := <-group.Acknowledge(entries[0].ID); acked != 1 {
+		t.Error("Should have acknowledged 1 entry, not", acked)
+	}
+	pending := <-group.Pending(PendingOptions{Consumer: "c1"})
+	if len(pending) != 2 || pending[0].Deliveries != 1 {
+		t.Error("c1 should have 2 pending entries, not", pending)
+	}
+	if history := <-group.ReadPending("c1", "0", 0); len(history) != 2 {
+		t.Error("c1 should be able to read its 2 pending entries again, not", history)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	claimed := <-group.AutoClaim("c2", 10*time.Millisecond, "0-0", 1)
+	if len(claimed.Entries) != 1 || claimed.Entries[0].ID != pending[0].ID {
+		t.Error("c2 should have claimed c1's first entry, not", claimed)
+	}
+	if entries := <-group.Claim("c2", 0, pending[1].ID); len(entries) != 1 {
+		t.Error("c2 should have claimed c1's second entry, not", entries)
+	}
+	if count := <-group.DeleteConsumer("c2"); count != 2 {
+		t.Error("c2 should have had 2 pending entries, not", count)
+	}
+
+	<-group.SetID("0")
+	if entries := <-group.Read("c3", 0); len(entries) != 3 {
+		t.Error("Should have read every entry again after resetting the group, not", entries)
+	}
+	if destroyed := <-group.Destroy(); !destroyed {
+		t.Error("Should have destroyed the group")
+	}
+}
+
+func TestWorker(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Stream("Test_Worker_Stream")
+	deadLetter := r.Stream("Test_Worker_DeadLetter")
+	<-s.Delete()
+	<-deadLetter.Delete()
+	group := s.Group("Test_Workers")
+	<-group.Create("0")
+
+	r.SetErrorCallback(func(error, string) {})
+
+	var lock sync.Mutex
+	handled := map[string]int{}
+	done := make(chan nothing, 10)
+	closer := group.Work(WorkerOptions{
+		Consumer:      "worker",
+		Concurrency:   3,
+		Block:         50 * time.Millisecond,
+		ClaimIdle:     50 * time.Millisecond,
+		MaxDeliveries: 2,
+		DeadLetter:    deadLetter,
+	}, func(entry StreamEntry) error {
+		lock.Lock()
+		handled[entry.Fields["n"]]++
+		lock.Unlock()
+		if entry.Fields["n"] == "bad" {
+			return errors.New("Bad entry")
+		}
+		done <- nothing{}
+		return nil
+	})
+
+	for _, n := range []string{"1", "2", "bad", "3"} {
+		<-s.Add(map[string]string{"n": n})
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			closer.Close()
+			t.Fatal("Timed out waiting for the entries to be handled")
+		}
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for <-deadLetter.Length() == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if entries := <-deadLetter.Range(StreamStart, StreamEnd, 0); len(entries) != 1 || entries[0].Fields["n"] != "bad" {
+		t.Error("The bad entry should have been moved to the dead letter stream, not", entries)
+	}
+	if summary := <-group.PendingSummary(); summary.Count != 0 {
+		t.Error("Every entry should have been acknowledged, not", summary)
+	}
+	lock.Lock()
+	if handled["1"] != 1 || handled["bad"] != 2 {
+		t.Error("Each good entry should have been handled once, and the bad one twice:", handled)
+	}
+	lock.Unlock()
+	closer.Close()
+}
+
+func TestWorkerPoisonedEntries(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.Stream("Test_Worker_Poisoned_Stream")
+	deadLetter := r.Stream("Test_Worker_Poisoned_DeadLetter")
+	<-s.Delete()
+	<-deadLetter.Delete()
+	group := s.Group("Test_Workers")
+	<-group.Create("0")
+
+	r.SetErrorCallback(func(error, string) {})
+
+	//there are more bad entries than get claimed at once, so they can only be given up on if the right ones are checked
+	var lock sync.Mutex
+	handled := map[string]int{}
+	closer := group.Work(WorkerOptions{
+		Consumer:      "worker",
+		Concurrency:   1,
+		Block:         20 * time.Millisecond,
+		ClaimIdle:     20 * time.Millisecond,
+		MaxDeliveries: 2,
+		DeadLetter:    deadLetter,
+	}, func(entry StreamEntry) error {
+		lock.Lock()
+		handled[entry.Fields["n"]]++
+		lock.Unlock()
+		return errors.New("Bad entry")
+	})
+
+	bad := []string{"1", "2", "3", "4", "5"}
+	for _, n := range bad {
+		<-s.Add(map[string]string{"n": n})
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for <-deadLetter.Length() < len(bad) && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	closer.Close()
+
+	if length := <-deadLetter.Length(); length != len(bad) {
+		t.Error("Every bad entry should have been moved to the dead letter stream, not", length)
+	}
+	if summary := <-group.PendingSummary(); summary.Count != 0 {
+		t.Error("Every entry should have been acknowledged, not", summary)
+	}
+	lock.Lock()
+	for _, n := range bad {
+		if handled[n] != 2 {
+			t.Error("Each bad entry should have been handled twice before giving up on it:", handled)
+			break
+		}
+	}
+	lock.Unlock()
+}
@@ -0,0 +1,545 @@
+package redis
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//A Subscriber listens to any number of pub/sub channels and patterns over a single connection.
+//Channels and patterns can be added and removed while it is listening, and each one has its own handler.
+//Handlers are called one at a time, in the order the messages were published
+//
+//If the connection is lost, the Subscriber reconnects (waiting longer after each failed attempt),
+//subscribes to every channel and pattern again, and lets the OnConfirmation handler know with a KindReconnect Message.
+//Commands issued while it is reconnecting are sent once it has reconnected
+type Subscriber struct {
+	client   *Client
+	messages chan Message
+	buffer   bufferPolicy
+	done     chan nothing
+
+	lock          sync.Mutex
+	conn          *Connection
+	connected     bool
+	channels      map[string]func(Message)
+	patterns      map[string]func(Message)
+	shards        map[string]func(Message)
+	confirmations func(Message)
+	pending       []*pendingSubscription
+	waiting       []*pendingSubscription
+	closed        bool
+}
+
+//pendingSubscription is a command that redis hasn't finished replying to yet;
+//redis confirms each channel or pattern of a (un)subscription separately, and replies to a PING with a single PONG
+type pendingSubscription struct {
+	args      []string
+	expected  int
+	replies   int
+	confirmed chan<- nothing
+	pong      chan<- string
+}
+
+func newPendingSubscription(args []string, confirmed chan<- nothing, pong chan<- string) *pendingSubscription {
+	expected := len(args) - 1
+	if pong != nil {
+		expected = 1
+	}
+	return &pendingSubscription{
+		args:      args,
+		expected:  expected,
+		replies:   expected,
+		confirmed: confirmed,
+		pong:      pong,
+	}
+}
+
+//Subscriber opens up a dedicated connection for a Subscriber,
+//which holds onto 64 messages while they wait to be handled, and stops reading from the connection until there's room for more.
+//(Warning - this is *not* a lightweight function - it opens up a new connection to redis)
+func (this *Client) Subscriber() (*Subscriber, error) {
+	return this.BufferedSubscriber(0, OverflowBlock)
+}
+
+//BufferedSubscriber opens up a dedicated connection for a Subscriber that holds onto "size" messages while they wait to be handled,
+//and deals with any more that arrive according to the policy, just like Channel.Buffered does.
+//The messages of every channel and pattern share the buffer, along with (un)subscription confirmations and PONGs
+//(which are only ever thrown away by OverflowDropOldest).
+//With OverflowDisconnect the whole Subscriber is closed, and any messages still in the buffer aren't handled.
+//(Warning - this is *not* a lightweight function - it opens up a new connection to redis)
+func (this *Client) BufferedSubscriber(size int, policy OverflowPolicy) (*Subscriber, error) {
+	conn, err := this.newConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bufferPolicy{
+		size:    size,
+		policy:  policy,
+		dropped: new(int64),
+	}
+	sub := &Subscriber{
+		client:    this,
+		messages:  make(chan Message, buffer.capacity()),
+		buffer:    buffer,
+		done:      make(chan nothing),
+		conn:      conn,
+		connected: true,
+		channels:  make(map[string]func(Message)),
+		patterns:  make(map[string]func(Message)),
+		shards:    make(map[string]func(Message)),
+	}
+	go sub.receive(conn)
+	go sub.dispatch()
+	return sub, nil
+}
+
+//SUBSCRIBE command -
+//Subscribe starts listening to the channels, calling the specified function whenever a message is published on any of them
+//(replacing the function previously used for any channel that was already being listened to).
+//It returns a channel that allows you to know when redis has confirmed the subscription;
+//if the subscription fails, the error is sent to the Client's error callback and the channel is closed without a value
+func (this *Subscriber) Subscribe(action func(string), channels ...Channel) <-chan nothing {
+	return this.SubscribeMessages(payloadOnly(action), channels...)
+}
+
+//SUBSCRIBE command -
+//SubscribeMessages is like Subscribe, but calls the specified function with each Message, which includes the channel it was published on
+func (this *Subscriber) SubscribeMessages(action func(Message), channels ...Channel) <-chan nothing {
+	return this.send(this.channels, action, "SUBSCRIBE", channels)
+}
+
+//PSUBSCRIBE command -
+//PatternSubscribe starts listening to every channel that fits the patterns (one for each Channel supplied),
+//calling the specified function whenever a message is published on any of them
+func (this *Subscriber) PatternSubscribe(action func(string), patterns ...Channel) <-chan nothing {
+	return this.PatternSubscribeMessages(payloadOnly(action), patterns...)
+}
+
+//PSUBSCRIBE command -
+//PatternSubscribeMessages is like PatternSubscribe, but calls the specified function with each Message,
+//which includes the channel it was published on and the pattern that it fit
+func (this *Subscriber) PatternSubscribeMessages(action func(Message), patterns ...Channel) <-chan nothing {
+	return this.send(this.patterns, action, "PSUBSCRIBE", patterns)
+}
+
+//UNSUBSCRIBE command -
+//Unsubscribe stops listening to the channels; their handlers aren't called again, even for messages that were already on their way
+func (this *Subscriber) Unsubscribe(channels ...Channel) <-chan nothing {
+	return this.send(this.channels, nil, "UNSUBSCRIBE", channels)
+}
+
+//PUNSUBSCRIBE command -
+//PatternUnsubscribe stops listening to the patterns
+func (this *Subscriber) PatternUnsubscribe(patterns ...Channel) <-chan nothing {
+	return this.send(this.patterns, nil, "PUNSUBSCRIBE", patterns)
+}
+
+//SSUBSCRIBE command -
+//ShardSubscribe starts listening to the shard channels, calling the specified function whenever a message is published on any of them (with ShardPublish).
+//When redis is running as a cluster, every channel in a single call has to belong to the same slot
+func (this *Subscriber) ShardSubscribe(action func(string), channels ...Channel) <-chan nothing {
+	return this.ShardSubscribeMessages(payloadOnly(action), channels...)
+}
+
+//SSUBSCRIBE command -
+//ShardSubscribeMessages is like ShardSubscribe, but calls the specified function with each Message
+func (this *Subscriber) ShardSubscribeMessages(action func(Message), channels ...Channel) <-chan nothing {
+	return this.send(this.shards, action, "SSUBSCRIBE", channels)
+}
+
+//SUNSUBSCRIBE command -
+//ShardUnsubscribe stops listening to the shard channels
+func (this *Subscriber) ShardUnsubscribe(channels ...Channel) <-chan nothing {
+	return this.send(this.shards, nil, "SUNSUBSCRIBE", channels)
+}
+
+//OnConfirmation calls the specified function with every (un)subscription confirmation and every PONG that redis sends back,
+//in order with the messages being received
+func (this *Subscriber) OnConfirmation(action func(Message)) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.confirmations = action
+}
+
+//PING command -
+//Ping checks that the connection is still working;
+//returns whatever was sent along with the PING once redis sends it back
+func (this *Subscriber) Ping(payload string) <-chan string {
+	pong := make(chan string, 1)
+	this.write(newPendingSubscription([]string{"PING", payload}, nil, pong))
+	return pong
+}
+
+//KeepAlive pings redis every so often, so that the connection isn't closed for being idle.
+//If redis doesn't reply before the next ping is due, the connection is treated as lost (and reconnected).
+//It returns a way to signal when you're done keeping the connection alive
+func (this *Subscriber) KeepAlive(interval time.Duration) (finishSignaler io.Closer) {
+	closer := make(chan bool, 1)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				this.lock.Lock()
+				conn, connected := this.conn, this.connected
+				this.lock.Unlock()
+				if !connected {
+					continue
+				}
+
+				select {
+				case _, ok := <-this.Ping(""):
+					if !ok {
+						return
+					}
+				case <-ticker.C:
+					this.lost(conn, errors.New("No reply to PING within "+interval.String()))
+				case <-closer:
+					return
+				}
+			case <-closer:
+				return
+			}
+		}
+	}()
+	subsc := (subscription)(closer)
+	return &subsc
+}
+
+//Channels returns the names of every channel being listened to, in alphabetical order
+func (this *Subscriber) Channels() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return sortedNames(this.channels)
+}
+
+//Patterns returns every pattern being listened to, in alphabetical order
+func (this *Subscriber) Patterns() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return sortedNames(this.patterns)
+}
+
+//ShardChannels returns the names of every shard channel being listened to, in alphabetical order
+func (this *Subscriber) ShardChannels() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return sortedNames(this.shards)
+}
+
+//Dropped returns the number of messages that have been thrown away because the buffer was full
+func (this *Subscriber) Dropped() int64 {
+	return atomic.LoadInt64(this.buffer.dropped)
+}
+
+//Close stops listening to everything, and closes the connection
+func (this *Subscriber) Close() error {
+	this.lock.Lock()
+	if this.closed {
+		this.lock.Unlock()
+		return errors.New("Already closed this subscriber")
+	}
+	this.closed = true
+	close(this.done)
+	for _, pending := range append(this.pending, this.waiting...) {
+		pending.finish()
+	}
+	this.pending = nil
+	this.waiting = nil
+	conn, connected := this.conn, this.connected
+	this.connected = false
+	this.lock.Unlock()
+
+	if !connected {
+		return nil
+	}
+	return conn.Close()
+}
+
+func sortedNames(handlers map[string]func(Message)) []string {
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//send updates the handlers (removing them if action is nil) and sends the command
+func (this *Subscriber) send(handlers map[string]func(Message), action func(Message), op string, channels []Channel) <-chan nothing {
+	confirmed := make(chan nothing, 1)
+	if len(channels) == 0 {
+		confirmed <- nothing{}
+		close(confirmed)
+		return confirmed
+	}
+
+	args := make([]string, 1, len(channels)+1)
+	args[0] = op
+	for _, channel := range channels {
+		args = append(args, channel.keyName())
+	}
+
+	this.write(newPendingSubscription(args, confirmed, nil), func() {
+		for _, name := range args[1:] {
+			if action == nil {
+				delete(handlers, name)
+			} else {
+				handlers[name] = action
+			}
+		}
+	})
+	return confirmed
+}
+
+//write sends a command while holding the lock (after making any changes that need to happen alongside it),
+//so that replies come back in the same order as the pending commands.
+//If the Subscriber is reconnecting, the command waits until it has reconnected
+func (this *Subscriber) write(pending *pendingSubscription, changes ...func()) {
+	this.lock.Lock()
+	if this.closed {
+		this.lock.Unlock()
+		pending.finish()
+		return
+	}
+
+	for _, change := range changes {
+		change()
+	}
+
+	if !this.connected {
+		this.waiting = append(this.waiting, pending)
+		this.lock.Unlock()
+		return
+	}
+
+	conn := this.conn
+	err := this.writeLocked(pending)
+	this.lock.Unlock()
+
+	if err != nil {
+		this.lost(conn, err)
+	}
+}
+
+//writeLocked sends a command on the current connection; the lock must already be held.
+//If it can't be sent, it waits to be sent again once the Subscriber has reconnected
+func (this *Subscriber) writeLocked(pending *pendingSubscription) error {
+	comm, err := buildCommand(pending.args)
+	if err != nil {
+		pending.finish()
+		this.client.errCallback(err, strings.Join(pending.args, " "))
+		return nil
+	}
+
+	if _, err = this.conn.Write(comm); err != nil {
+		this.waiting = append(this.waiting, pending)
+		return err
+	}
+	this.pending = append(this.pending, pending)
+	return nil
+}
+
+func (this *pendingSubscription) finish() {
+	if this.confirmed != nil {
+		close(this.confirmed)
+	}
+	if this.pong != nil {
+		close(this.pong)
+	}
+}
+
+func (this *pendingSubscription) succeed(m Message) {
+	if this.confirmed != nil {
+		this.confirmed <- nothing{}
+	}
+	if this.pong != nil {
+		this.pong <- m.Payload
+	}
+	this.finish()
+}
+
+//confirm counts a reply (or failure) towards the oldest pending command
+func (this *Subscriber) confirm(conn *Connection, m Message, err error) {
+	this.lock.Lock()
+	if conn != this.conn || len(this.pending) == 0 {
+		this.lock.Unlock()
+		return
+	}
+
+	head := this.pending[0]
+	head.replies--
+	if err != nil {
+		head.replies = 0
+		head.finish()
+	} else if head.replies == 0 {
+		head.succeed(m)
+	}
+	if head.replies == 0 {
+		this.pending = this.pending[1:]
+	}
+	this.lock.Unlock()
+
+	if err != nil {
+		this.client.errCallback(err, strings.Join(head.args, " "))
+	}
+}
+
+//lost deals with a connection no longer working, by reconnecting in the background.
+//Any commands that were still waiting on a reply are sent again once it has reconnected
+func (this *Subscriber) lost(conn *Connection, err error) {
+	this.lock.Lock()
+	if this.closed || !this.connected || conn != this.conn {
+		this.lock.Unlock()
+		return
+	}
+	this.connected = false
+	for _, pending := range this.pending {
+		pending.replies = pending.expected
+	}
+	this.waiting = append(this.pending, this.waiting...)
+	this.pending = nil
+	this.lock.Unlock()
+
+	conn.Close()
+	this.client.errCallback(err, "Subscriber")
+	go this.reconnect(err)
+}
+
+//reconnect keeps trying to open a new connection until it succeeds (or the Subscriber is closed),
+//then subscribes to everything again, and sends any commands that were waiting
+func (this *Subscriber) reconnect(cause error) {
+	delay := reconnectMinDelay
+	for {
+		select {
+		case <-time.After(delay):
+		case <-this.done:
+			return
+		}
+
+		conn, err := this.client.newConnection()
+		if err != nil {
+			this.client.errCallback(err, "Reconnecting Subscriber")
+			delay = nextReconnectDelay(delay)
+			continue
+		}
+
+		//the gap is reported ahead of anything that arrives on the new connection
+		select {
+		case this.messages <- Message{Kind: KindReconnect, Payload: cause.Error()}:
+		case <-this.done:
+			conn.Close()
+			return
+		}
+
+		this.lock.Lock()
+		if this.closed {
+			this.lock.Unlock()
+			conn.Close()
+			return
+		}
+		this.conn = conn
+		this.connected = true
+
+		waiting := this.waiting
+		this.waiting = nil
+		resubscribe := []*pendingSubscription{}
+		if channels := sortedNames(this.channels); len(channels) > 0 {
+			resubscribe = append(resubscribe, newPendingSubscription(append([]string{"SUBSCRIBE"}, channels...), nil, nil))
+		}
+		if patterns := sortedNames(this.patterns); len(patterns) > 0 {
+			resubscribe = append(resubscribe, newPendingSubscription(append([]string{"PSUBSCRIBE"}, patterns...), nil, nil))
+		}
+		if shards := sortedNames(this.shards); len(shards) > 0 {
+			resubscribe = append(resubscribe, newPendingSubscription(append([]string{"SSUBSCRIBE"}, shards...), nil, nil))
+		}
+		for _, pending := range append(resubscribe, waiting...) {
+			if err = this.writeLocked(pending); err != nil {
+				break
+			}
+		}
+		this.lock.Unlock()
+
+		go this.receive(conn)
+		if err != nil {
+			this.lost(conn, err)
+		}
+		return
+	}
+}
+
+//receive reads everything that redis sends back on a connection until it stops working
+func (this *Subscriber) receive(conn *Connection) {
+	for {
+		r, err := getResponse(conn)
+		if _, ok := err.(ReplyError); ok {
+			this.confirm(conn, Message{}, err)
+			continue
+		}
+		if err != nil {
+			this.lost(conn, err)
+			return
+		}
+
+		m, ok := parseMessage(r)
+		if !ok {
+			continue
+		}
+		if !m.IsMessage() {
+			this.confirm(conn, m, nil)
+			select {
+			case this.messages <- m:
+			case <-this.done:
+				return
+			}
+			continue
+		}
+
+		carryOn, overflowed := this.buffer.deliver(this.messages, m, this.done)
+		if overflowed {
+			this.Close()
+			this.client.errCallback(errSlowConsumer, "Subscriber")
+			return
+		}
+		if !carryOn {
+			return
+		}
+	}
+}
+
+//dispatch calls the handler of each message, looking it up as late as possible so that unsubscribed handlers aren't called
+func (this *Subscriber) dispatch() {
+	for {
+		var m Message
+		select {
+		case m = <-this.messages:
+		case <-this.done:
+			return
+		}
+
+		this.lock.Lock()
+		var action func(Message)
+		switch m.Kind {
+		case KindMessage:
+			action = this.channels[m.Channel]
+		case KindPatternMessage:
+			action = this.patterns[m.Pattern]
+		case KindShardMessage:
+			action = this.shards[m.Channel]
+		default:
+			action = this.confirmations
+		}
+		this.lock.Unlock()
+
+		if action != nil {
+			action(m)
+		}
+	}
+}
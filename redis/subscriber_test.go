@@ -0,0 +1,225 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscriber(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	sub, err := r.Subscriber()
+	if err != nil {
+		t.Fatal("Couldn't create subscriber -", err)
+	}
+	defer sub.Close()
+
+	messages := make(chan string, 16)
+	received := func(prefix string) func(string) {
+		return func(message string) {
+			messages <- prefix + message
+		}
+	}
+
+	p := r.Prefix("Test_Subscriber:")
+	channels := make([]Channel, 100)
+	for i := range channels {
+		channels[i] = p.Channel("user" + itoa(i))
+	}
+	if _, ok := <-sub.Subscribe(received("user:"), channels...); !ok {
+		t.Fatal("Should have subscribed to every channel")
+	}
+	if _, ok := <-sub.PatternSubscribe(received("pattern:"), p.Channel("group*")); !ok {
+		t.Fatal("Should have subscribed to the pattern")
+	}
+	if res := sub.Channels(); len(res) != 100 || res[0] != "Test_Subscriber:user0" {
+		t.Error("Should be listening to 100 channels, not", res)
+	}
+	if res := sub.Patterns(); len(res) != 1 || res[0] != "Test_Subscriber:group*" {
+		t.Error("Should be listening to a single pattern, not", res)
+	}
+
+	expect := func(expected string) {
+		select {
+		case message := <-messages:
+			if message != expected {
+				t.Error("Should have received", expected, "not", message)
+			}
+		case <-time.After(time.Second):
+			t.Error("Should have received", expected)
+		}
+	}
+
+	<-channels[42].Publish("A")
+	expect("user:A")
+	<-p.Channel("group1").Publish("B")
+	expect("pattern:B")
+
+	<-sub.Unsubscribe(channels[42])
+	<-sub.Subscribe(received("replaced:"), channels[43])
+	<-channels[42].Publish("C")
+	<-channels[43].Publish("D")
+	expect("replaced:D")
+	if res := sub.Channels(); len(res) != 99 {
+		t.Error("Should be listening to 99 channels, not", len(res))
+	}
+
+	<-sub.PatternUnsubscribe(p.Channel("group*"))
+	<-p.Channel("group1").Publish("E")
+	<-channels[0].Publish("F")
+	expect("user:F")
+
+	if sub.Close() != nil {
+		t.Error("Should have closed the subscriber")
+	}
+	if _, ok := <-sub.Subscribe(received("closed:"), channels[0]); ok {
+		t.Error("Shouldn't be able to subscribe once closed")
+	}
+	if sub.Close() == nil {
+		t.Error("Shouldn't be able to close the subscriber twice")
+	}
+}
+
+func TestSubscriberMessages(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	sub, err := r.Subscriber()
+	if err != nil {
+		t.Fatal("Couldn't create subscriber -", err)
+	}
+	defer sub.Close()
+
+	confirmations := make(chan Message, 8)
+	sub.OnConfirmation(func(m Message) {
+		confirmations <- m
+	})
+
+	if res, ok := <-sub.Ping("before"); !ok || res != "before" {
+		t.Error("Should have gotten a PONG before subscribing to anything, not", res)
+	}
+	messages := make(chan Message, 8)
+	p := r.Prefix("Test_Subscriber_Messages:")
+	<-sub.PatternSubscribeMessages(func(m Message) {
+		messages <- m
+	}, p.Channel("*"))
+	<-sub.SubscribeMessages(func(m Message) {
+		messages <- m
+	}, p.Channel("A"), p.Channel("B"))
+
+	if res, ok := <-sub.Ping("after"); !ok || res != "after" {
+		t.Error("Should have gotten the payload back from PING, not", res)
+	}
+
+	expected := []Message{
+		{Kind: KindPong, Payload: "before"},
+		{Kind: KindPatternSubscribe, Pattern: "Test_Subscriber_Messages:*", Count: 1},
+		{Kind: KindSubscribe, Channel: "Test_Subscriber_Messages:A", Count: 2},
+		{Kind: KindSubscribe, Channel: "Test_Subscriber_Messages:B", Count: 3},
+		{Kind: KindPong, Payload: "after"},
+	}
+	for _, e := range expected {
+		select {
+		case m := <-confirmations:
+			if m != e {
+				t.Error("Should have been sent", e, "not", m)
+			}
+		case <-time.After(time.Second):
+			t.Error("Should have been sent", e)
+		}
+	}
+
+	<-p.Channel("B").Publish("Test")
+	received := map[Message]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case m := <-messages:
+			received[m] = true
+		case <-time.After(time.Second):
+			t.Error("Should have received the message twice")
+		}
+	}
+	if !received[Message{Kind: KindMessage, Channel: "Test_Subscriber_Messages:B", Payload: "Test"}] ||
+		!received[Message{Kind: KindPatternMessage, Channel: "Test_Subscriber_Messages:B", Pattern: "Test_Subscriber_Messages:*", Payload: "Test"}] {
+		t.Error("Messages should have described where they came from, not", received)
+	}
+
+	closer := sub.KeepAlive(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	closer.Close()
+	if res, ok := <-sub.Ping(""); !ok || res != "" {
+		t.Error("Keeping the connection alive shouldn't have broken it")
+	}
+}
+
+func TestSubscriberReconnect(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	lost := make(chan string, 4)
+	r.SetErrorCallback(func(e error, s string) {
+		lost <- s
+	})
+
+	sub, err := r.Subscriber()
+	if err != nil {
+		t.Fatal("Couldn't create subscriber -", err)
+	}
+	defer sub.Close()
+
+	confirmations := make(chan Message, 8)
+	sub.OnConfirmation(func(m Message) {
+		confirmations <- m
+	})
+	messages := make(chan string, 8)
+	channel := r.Channel("Test_Subscriber_Reconnect")
+	<-sub.Subscribe(func(message string) {
+		messages <- message
+	}, channel)
+	<-sub.PatternSubscribe(func(message string) {
+		messages <- "pattern:" + message
+	}, r.Channel("Test_Subscriber_Reconnect*"))
+	<-confirmations
+	<-confirmations
+
+	//simulate the connection dropping
+	sub.lock.Lock()
+	sub.conn.Close()
+	sub.lock.Unlock()
+
+	select {
+	case res := <-lost:
+		if res != "Subscriber" {
+			t.Error("Should have reported the lost connection, not", res)
+		}
+	case <-time.After(time.Second):
+		t.Error("Should have reported the lost connection")
+	}
+
+	expected := []string{KindReconnect, KindSubscribe, KindPatternSubscribe}
+	for _, kind := range expected {
+		select {
+		case m := <-confirmations:
+			if m.Kind != kind {
+				t.Error("Should have been sent", kind, "not", m)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Should have been sent", kind)
+		}
+	}
+
+	<-channel.Publish("A")
+	received := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case m := <-messages:
+			received[m] = true
+		case <-time.After(time.Second):
+			t.Error("Should have received the message twice after reconnecting")
+		}
+	}
+	if !received["A"] || !received["pattern:A"] {
+		t.Error("Should have received the message on the channel and the pattern, not", received)
+	}
+}
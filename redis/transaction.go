@@ -1,9 +1,61 @@
 package redis
 
-import ()
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	//ErrDiscarded is the error recorded when a Transaction is discarded before it gets sent to Redis
+	ErrDiscarded = errors.New("Transaction was discarded")
+
+	//ErrAborted is the error recorded when Redis refuses to execute a Transaction that it has already queued
+	ErrAborted = errors.New("Transaction was aborted by Redis")
+
+	errClosed = errors.New("Redis is closed")
+)
+
+//CommandError is the error recorded for a single command sent through a Pipeline or Transaction
+type CommandError struct {
+	Arguments []string
+	Err       error
+}
+
+func (this CommandError) Error() string {
+	return this.Err.Error() + ":" + strings.Join(this.Arguments, " ")
+}
+
+//PipeResult describes what happened to the commands issued within a Pipeline or Transaction
+type PipeResult struct {
+	//Aborted is true when a Transaction was discarded or Redis refused to execute it; none of its commands took effect
+	Aborted bool
+
+	//Err is an error that applied to the whole pipe (e.g. the connection failed, or Redis aborted the Transaction)
+	Err error
+
+	//Errors holds the error for each command, in the order they were issued (so the error of the third command issued is Errors[2]);
+	//the entry is nil if the command succeeded.
+	//Each of these errors is also sent to the error callback (if one is set) along with the command's arguments, once every reply has been read.
+	//Errors that applied to the whole pipe (see Err) are only found here
+	Errors []error
+}
+
+//Succeeded returns whether every command within the pipe was executed without an error
+func (this PipeResult) Succeeded() bool {
+	if this.Aborted || this.Err != nil {
+		return false
+	}
+	for _, err := range this.Errors {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
 
 type pipe struct {
 	commands     []command
+	failed       []int
 	fErrCallback errCallbackFunc
 }
 
@@ -15,67 +67,207 @@ func (this *pipe) errCallback(err error, s string) {
 	this.fErrCallback.Call(err, s)
 }
 
-func (this Client) piping(callback func(SafeExecutor) bool, queued bool) {
+//record keeps track of an error caused by a single command, so that it can be reported once the pipe is done with its connection
+func (this *pipe) record(index int, err error, result *PipeResult) {
+	result.Errors[index] = CommandError{this.commands[index].arguments(), err}
+	this.failed = append(this.failed, index)
+}
+
+//report sends the errors of single commands to the error callback along with the command's arguments, the same way a Connection does.
+//Unlike a Connection, a pipe has nobody to panic at when there is no callback, so the errors are only kept in the result
+func (this *pipe) report(result PipeResult) {
+	if this.fErrCallback == nil {
+		return
+	}
+	for _, index := range this.failed {
+		err := result.Errors[index].(CommandError)
+		this.fErrCallback(err.Err, strings.Join(err.Arguments, " "))
+	}
+}
+
+//fail records an error against a single command, and closes it out without a result
+func (this *pipe) fail(index int, err error, result *PipeResult) {
+	this.record(index, err, result)
+	this.commands[index].callback()(nil)
+}
+
+//abandon closes out every command from "start" onward without a result
+//(commands that already have an error recorded keep it, so that the cause of an aborted Transaction can be found)
+func (this *pipe) abandon(start int, err error, result *PipeResult) {
+	for i := start; i < len(this.commands); i++ {
+		if result.Errors[i] == nil {
+			result.Errors[i] = CommandError{this.commands[i].arguments(), err}
+		}
+		this.commands[i].callback()(nil)
+	}
+}
+
+//deliver hands a response off to a command, keeping track of any error that it causes
+func (this *pipe) deliver(index int, res *response, result *PipeResult) {
+	if res != nil && res.err != nil {
+		this.fail(index, res.err, result)
+		return
+	}
+	if err := this.commands[index].callback()(res); err != nil {
+		this.record(index, err, result)
+	}
+}
+
+func (this *pipe) readPipeline(conn *Connection, result *PipeResult) {
+	for i := range this.commands {
+		res, err := getResponse(conn)
+		if _, ok := err.(ReplyError); ok {
+			this.fail(i, err, result)
+			continue
+		}
+		if err != nil {
+			//we can no longer tell which reply belongs to which command
+			result.Err = err
+			this.abandon(i, err, result)
+			return
+		}
+		this.deliver(i, res, result)
+	}
+}
+
+func (this *pipe) readTransaction(conn *Connection, result *PipeResult) {
+	//MULTI
+	if _, err := getResponse(conn); err != nil {
+		result.Aborted = true
+		result.Err = err
+		this.abandon(0, err, result)
+		return
+	}
+
+	//each command will get a "QUEUED" reply, unless Redis refuses to queue it
+	for i := range this.commands {
+		_, err := getResponse(conn)
+		if _, ok := err.(ReplyError); ok {
+			this.record(i, err, result)
+		} else if err != nil {
+			result.Aborted = true
+			result.Err = err
+			this.abandon(0, err, result)
+			return
+		}
+	}
+
+	//EXEC
+	res, err := getResponse(conn)
+	if err == nil && res == nil {
+		err = ErrAborted
+	}
+	if err != nil {
+		result.Aborted = true
+		result.Err = err
+		this.abandon(0, err, result)
+		return
+	}
+
+	//commands that couldn't be queued don't get a reply within EXEC
+	next := 0
+	for i := range this.commands {
+		if result.Errors[i] != nil {
+			this.commands[i].callback()(nil)
+			continue
+		}
+		var sub *response
+		if next < len(res.subresponses) {
+			sub = res.subresponses[next]
+		}
+		next++
+		this.deliver(i, sub, result)
+	}
+}
+
+func (this Client) flush(p *pipe, proceed, queued bool) (result PipeResult) {
+	result.Errors = make([]error, len(p.commands))
+	defer func() {
+		//only once the connection is back in the pool, so that the callback can issue commands of its own
+		p.report(result)
+	}()
+	if !proceed {
+		//nothing has been sent yet, so there is nothing for redis to discard
+		result.Aborted = true
+		result.Err = ErrDiscarded
+		p.abandon(0, ErrDiscarded, &result)
+		return
+	}
+
+	var bundle []byte
+	if queued {
+		bundle, _ = buildCommand([]string{"MULTI"})
+	}
+	for _, command := range p.commands {
+		comm, err := buildCommand(command.arguments())
+		if err != nil {
+			result.Err = err
+			p.abandon(0, err, &result)
+			return
+		}
+		bundle = append(bundle, comm...)
+	}
+	if queued {
+		exec, _ := buildCommand([]string{"EXEC"})
+		bundle = append(bundle, exec...)
+	}
+
+	sent := false
+	this.useConnection(func(c *Connection) {
+		sent = true
+		if _, err := c.Write(bundle); err != nil {
+			result.Aborted = queued
+			result.Err = err
+			p.abandon(0, err, &result)
+			return
+		}
+		if queued {
+			p.readTransaction(c, &result)
+		} else {
+			p.readPipeline(c, &result)
+		}
+	})
+	if !sent {
+		result.Aborted = queued
+		result.Err = errClosed
+		p.abandon(0, errClosed, &result)
+	}
+	return
+}
+
+func (this Client) piping(callback func(SafeExecutor) bool, queued bool) (result PipeResult) {
 	p := new(pipe)
 	p.commands = make([]command, 0, 5)
 	p.fErrCallback = this.fErrCallback
-	var result bool
+	proceed := false
 	defer func() {
-		var bundle []byte
-		for _, command := range p.commands {
-			comm, err := buildCommand(command.arguments())
-			if err != nil {
-				this.errCallback(err, "piping")
-			}
-			bundle = append(bundle, comm...)
-		}
-		this.useConnection(func(c *Connection) {
-			c.Write(bundle)
-			if !result {
-				//everything was discarded - just get basic result and don't bother waiting for everything else
-				getResponse(c)
-				return
-			}
-			if queued {
-				//get rid of all of the "queued" responses
-				for i := 0; i < len(p.commands)-1; i++ {
-					getResponse(c)
-				}
-				//the first reply is going to be a multi-bulk, with all of the other replies as subresponses
-				//get rid of the multi-bulk, and just get the other replies as normal
-				//(this is a little bit hacky, perhaps I'll make it less so in future versions)
-				getString(c)
-				p.commands = p.commands[1 : len(p.commands)-1]
-			}
-			for _, command := range p.commands {
-				c.output(command)
-			}
-		})
+		result = this.flush(p, proceed, queued)
 	}()
-	result = callback(p)
+	proceed = callback(p)
+	return
 }
 
 //Pipeline creates an Executor that will force every command issued on it to be sent at the same time (thus saving on network costs).
-//It waits until the end of the function to execute them
-func (this Client) Pipeline(callback func(SafeExecutor)) {
-	this.piping(func(e SafeExecutor) bool {
+//It waits until the end of the function to execute them, and returns what happened to each of them.
+//Commands that fail will not send anything back through their channels;
+//their errors are sent to the error callback (if one is set), and can be found in the result by the order the commands were issued in
+func (this Client) Pipeline(callback func(SafeExecutor)) PipeResult {
+	return this.piping(func(e SafeExecutor) bool {
 		callback(e)
 		return true
 	}, false)
 }
 
 //Transaction creates an Executor that will tell redis to queue all of the commands and complete them atomically
-//(this prevents other clients from issuing commands in between yours)
-func (this Client) Transaction(callback func(SafeExecutor)) {
-	this.piping(func(p SafeExecutor) (result bool) {
-		NilCommand(p, "MULTI")
+//(this prevents other clients from issuing commands in between yours).
+//If the function panics, the Transaction is discarded.
+//The result tells you whether the Transaction was carried out, and which of its commands failed;
+//commands that fail will not send anything back through their channels, and their errors are sent to the error callback (if one is set) as well (e.g. WRONGTYPE)
+func (this Client) Transaction(callback func(SafeExecutor)) PipeResult {
+	return this.piping(func(p SafeExecutor) (proceed bool) {
 		defer func() {
-			rec := recover()
-			if rec == nil {
-				NilCommand(p, "EXEC")
-			} else {
-				NilCommand(p, "DISCARD")
-				result = false
+			if rec := recover(); rec != nil {
+				proceed = false
 			}
 		}()
 
@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -115,3 +116,176 @@ func TestTransaction(t *testing.T) {
 		t.Error("c should be C")
 	}
 }
+
+func TestTransactionResults(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.String("Transaction_Result_String")
+	l := r.List("Transaction_Result_List")
+
+	<-s.Delete()
+	<-l.Delete()
+	<-l.LeftPush("A")
+
+	reported := make(chan string, 2)
+	r.SetErrorCallback(func(err error, command string) {
+		reported <- command + ": " + err.Error()
+	})
+
+	var set <-chan nothing
+	var get, wrongType <-chan string
+	result := r.Transaction(func(e SafeExecutor) {
+		set = s.Use(e).Set("B")
+		wrongType = String{l.Key}.Use(e).Get()
+		get = s.Use(e).Get()
+	})
+
+	if result.Aborted || result.Err != nil {
+		t.Error("Transaction should have gone through, instead got", result.Err)
+	}
+	if result.Succeeded() {
+		t.Error("Transaction should report that one of its commands failed")
+	}
+	if len(result.Errors) != 3 {
+		t.Fatal("Should have a result for each command, not", len(result.Errors))
+	}
+	if result.Errors[0] != nil || result.Errors[2] != nil {
+		t.Error("Only the second command should have failed")
+	}
+	if err := result.Errors[1]; err == nil || !strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		t.Error("Second command should have a WRONGTYPE error, not", err)
+	}
+	if _, ok := <-set; !ok {
+		t.Error("Set should have succeeded")
+	}
+	if res, ok := <-wrongType; ok {
+		t.Error("Should not get anything back from a failed command, and definitely not", res)
+	}
+	select {
+	case report := <-reported:
+		if !strings.HasPrefix(report, "GET Transaction_Result_List: WRONGTYPE") {
+			t.Error("The WRONGTYPE error should be reported along with the failed command, not", report)
+		}
+	default:
+		t.Error("The WRONGTYPE error should be sent to the error callback")
+	}
+	if res := <-get; res != "B" {
+		t.Error("Should have gotten B, not", res)
+	}
+
+	result = r.Transaction(func(e SafeExecutor) {
+		set = s.Use(e).Set("C")
+		panic("discard")
+	})
+	if !result.Aborted || result.Err != ErrDiscarded {
+		t.Error("Transaction should have been discarded, not", result.Err)
+	}
+	if _, ok := <-set; ok {
+		t.Error("Discarded command should not return anything")
+	}
+	if res := <-s.Get(); res != "B" {
+		t.Error("Should still be B, not", res)
+	}
+
+	result = r.Transaction(func(e SafeExecutor) {
+		set = s.Use(e).Set("D")
+		NilCommand(e, "INVALIDCOMMAND")
+	})
+	if !result.Aborted || result.Err == nil {
+		t.Error("Redis should have refused to execute the transaction")
+	}
+	if len(result.Errors) != 2 || result.Errors[1] == nil {
+		t.Error("The invalid command should have its own error")
+	}
+	select {
+	case report := <-reported:
+		if !strings.HasPrefix(report, "INVALIDCOMMAND: ") {
+			t.Error("The invalid command's error should be reported along with it, not", report)
+		}
+	default:
+		t.Error("The invalid command's error should be sent to the error callback")
+	}
+	if _, ok := <-set; ok {
+		t.Error("Aborted command should not return anything")
+	}
+	if res := <-s.Get(); res != "B" {
+		t.Error("Should still be B, not", res)
+	}
+}
+
+func TestPipelineResults(t *testing.T) {
+	r := GetRedis(t)
+	defer r.Close()
+
+	s := r.String("Pipeline_Result_String")
+	<-s.Delete()
+
+	reported := make(chan string, 1)
+	r.SetErrorCallback(func(err error, command string) {
+		reported <- command
+	})
+
+	result := r.Pipeline(func(e SafeExecutor) {
+		s.Use(e).Set("A")
+		NilCommand(e, "INVALIDCOMMAND")
+		s.Use(e).Append("B")
+	})
+
+	if result.Aborted || result.Err != nil {
+		t.Error("Pipeline should have been sent, instead got", result.Err)
+	}
+	if len(result.Errors) != 3 || result.Errors[0] != nil || result.Errors[1] == nil || result.Errors[2] != nil {
+		t.Error("Only the invalid command should have failed, got", result.Errors)
+	}
+	if command := <-reported; command != "INVALIDCOMMAND" {
+		t.Error("The invalid command's error should be reported along with it, not", command)
+	}
+	if res := <-s.Get(); res != "AB" {
+		t.Error("Should have gotten AB, not", res)
+	}
+}
+
+func TestPipelineWithoutErrorCallback(t *testing.T) {
+	config := DefaultConfiguration()
+	config.ConnectionCount = 1
+	r, err := New(config)
+	if err != nil {
+		t.Fatal("Can't load redis - " + err.Error())
+	}
+	defer r.Close()
+
+	s := r.String("Pipeline_NoCallback_String")
+	l := r.List("Pipeline_NoCallback_List")
+	<-s.Delete()
+	<-l.Delete()
+	<-l.LeftPush("A")
+
+	//a failed command must not stop the rest of the replies from being read off the connection
+	var get <-chan string
+	result := r.Pipeline(func(e SafeExecutor) {
+		String{l.Key}.Use(e).Get()
+		s.Use(e).Set("B")
+		get = s.Use(e).Get()
+	})
+	if len(result.Errors) != 3 || result.Errors[0] == nil || !strings.HasPrefix(result.Errors[0].Error(), "WRONGTYPE") {
+		t.Error("The first command should have a WRONGTYPE error, not", result.Errors)
+	}
+	if res := <-get; res != "B" {
+		t.Error("Should have gotten B within the pipeline, not", res)
+	}
+	if res := <-s.Append("C"); res != 2 {
+		t.Error("Should have appended to B after the pipeline, not gotten", res)
+	}
+
+	result = r.Transaction(func(e SafeExecutor) {
+		String{l.Key}.Use(e).Get()
+		s.Use(e).Set("D")
+	})
+	if result.Aborted || result.Errors[0] == nil || result.Errors[1] != nil {
+		t.Error("Only the first command of the transaction should have failed, not", result.Errors)
+	}
+	if res := <-s.Get(); res != "D" {
+		t.Error("Should have gotten D after the transaction, not", res)
+	}
+}
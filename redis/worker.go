@@ -0,0 +1,204 @@
+package redis
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	workerDefaultBlock = time.Second
+)
+
+//WorkerOptions describes how a worker started with Work processes the entries of a consumer group
+type WorkerOptions struct {
+	//Consumer is the name the worker reads as; every worker in a group should have its own name
+	Consumer string
+
+	//Concurrency is the most entries that are handled at once (1 if left at 0)
+	Concurrency int
+
+	//Block is how long each read waits for new entries before checking whether the worker has been stopped (a second if left at 0)
+	Block time.Duration
+
+	//ClaimIdle reclaims the entries that have been pending for this long (because their handler failed, or their consumer went away),
+	//so that they're handled again. Nothing is reclaimed if it is left at 0
+	ClaimIdle time.Duration
+
+	//MaxDeliveries gives up on entries that have been delivered this many times without being handled successfully,
+	//moving them to DeadLetter (or just acknowledging them, if there isn't one).
+	//Entries are retried indefinitely if it is left at 0; it only applies to entries that are reclaimed
+	MaxDeliveries int
+
+	//DeadLetter is the stream that the entries that were given up on are added to (with the same fields)
+	DeadLetter Stream
+}
+
+func (this WorkerOptions) concurrency() int {
+	if this.Concurrency <= 0 {
+		return 1
+	}
+	return this.Concurrency
+}
+
+func (this WorkerOptions) block() time.Duration {
+	if this.Block <= 0 {
+		return workerDefaultBlock
+	}
+	return this.Block
+}
+
+type worker struct {
+	group   ConsumerGroup
+	options WorkerOptions
+	handler func(StreamEntry) error
+	slots   chan nothing
+
+	done     chan nothing
+	closing  sync.Once
+	stopped  chan nothing
+	handlers sync.WaitGroup
+
+	lastClaim time.Time
+	claimFrom string
+}
+
+//Work keeps delivering entries to the consumer, calling the handler with each of them (in its own goroutine, up to Concurrency at once),
+//and acknowledging the ones that it handles without an error.
+//Errors are sent to the error callback, and the entry stays pending so that it can be reclaimed (see WorkerOptions).
+//The group must already have been created.
+//It returns a way to signal when you're done working; closing it waits until the entries that are already being handled have been acknowledged
+func (this ConsumerGroup) Work(options WorkerOptions, handler func(StreamEntry) error) (finishSignaler io.Closer) {
+	w := &worker{
+		group:     this,
+		options:   options,
+		handler:   handler,
+		slots:     make(chan nothing, options.concurrency()),
+		done:      make(chan nothing),
+		stopped:   make(chan nothing),
+		claimFrom: "0-0",
+	}
+	go w.run()
+	return w
+}
+
+//Close stops the worker, and waits for it to finish what it was doing
+func (this *worker) Close() error {
+	err := errors.New("Already closed this worker")
+	this.closing.Do(func() {
+		err = nil
+		close(this.done)
+	})
+	<-this.stopped
+	this.handlers.Wait()
+	return err
+}
+
+func (this *worker) run() {
+	defer close(this.stopped)
+	for {
+		//wait for room to handle at least one entry, then take as much room as there is
+		select {
+		case this.slots <- nothing{}:
+		case <-this.done:
+			return
+		}
+		free := 1
+	room:
+		for free < cap(this.slots) {
+			select {
+			case this.slots <- nothing{}:
+				free++
+			default:
+				break room
+			}
+		}
+
+		entries := this.reclaim(free)
+		if len(entries) == 0 {
+			started := time.Now()
+			read, ok := <-this.group.BlockUntilRead(this.options.Consumer, free, this.options.block())
+			entries = read
+			if !ok && time.Since(started) < this.options.block()/2 {
+				//the read failed rather than timing out, so wait a bit instead of failing over and over
+				select {
+				case <-time.After(this.options.block()):
+				case <-this.done:
+				}
+			}
+		}
+
+		for i := len(entries); i < free; i++ {
+			<-this.slots
+		}
+		this.handlers.Add(len(entries))
+		for _, entry := range entries {
+			go this.handle(entry)
+		}
+
+		select {
+		case <-this.done:
+			return
+		default:
+		}
+	}
+}
+
+func (this *worker) handle(entry StreamEntry) {
+	defer this.handlers.Done()
+	defer func() { <-this.slots }()
+	if err := this.handler(entry); err != nil {
+		this.group.stream.client.errCallback(err, "Handling entry "+entry.ID+" of "+this.group.stream.key)
+		return
+	}
+	<-this.group.Acknowledge(entry.ID)
+}
+
+//reclaim claims up to "count" of the entries that have been pending for too long, and gives up on the ones that have been delivered too many times.
+//It only looks for them once every ClaimIdle
+func (this *worker) reclaim(count int) []StreamEntry {
+	if this.options.ClaimIdle <= 0 || time.Since(this.lastClaim) < this.options.ClaimIdle {
+		return nil
+	}
+
+	claimed, ok := <-this.group.AutoClaim(this.options.Consumer, this.options.ClaimIdle, this.claimFrom, count)
+	if !ok {
+		return nil
+	}
+	this.claimFrom = claimed.Next
+	if this.claimFrom == "0-0" {
+		//every pending entry has been looked at, so wait a while before looking again
+		this.lastClaim = time.Now()
+	}
+	if this.options.MaxDeliveries <= 0 {
+		return claimed.Entries
+	}
+
+	//look up exactly the entries that were claimed - claiming them counts as delivering them again
+	pending := make([]<-chan []PendingEntry, len(claimed.Entries))
+	for i, entry := range claimed.Entries {
+		pending[i] = this.group.Pending(PendingOptions{Start: entry.ID, End: entry.ID, Count: 1})
+	}
+	entries := make([]StreamEntry, 0, len(claimed.Entries))
+	for i, entry := range claimed.Entries {
+		if details, ok := <-pending[i]; ok && len(details) == 1 && details[0].Deliveries > this.options.MaxDeliveries {
+			this.giveUp(entry, details[0].Deliveries-1)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+//giveUp moves an entry to the dead letter stream, and acknowledges it
+func (this *worker) giveUp(entry StreamEntry, deliveries int) {
+	stream := this.group.stream
+	if this.options.DeadLetter.key != "" {
+		if _, ok := <-this.options.DeadLetter.Add(entry.Fields); !ok {
+			return
+		}
+	}
+	stream.client.errCallback(errors.New("Gave up after "+itoa(deliveries)+" deliveries"), "Handling entry "+entry.ID+" of "+stream.key)
+	<-this.group.Acknowledge(entry.ID)
+}